@@ -3,88 +3,182 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
+	"flag"
 	"net/http"
 	"os"
 	"os/signal"
-	"rainmcp/pkg/models"
 	"syscall"
 	"time"
 
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/auth"
+	"rainmcp/pkg/completion"
 	"rainmcp/pkg/logger"
+	"rainmcp/pkg/prompts"
 	"rainmcp/pkg/services"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/watch"
 
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/ThinkInAIXYZ/go-mcp/server"
 	"github.com/ThinkInAIXYZ/go-mcp/transport"
 )
 
+const (
+	// transportSSE 通过HTTP SSE暴露MCP服务
+	transportSSE = "sse"
+	// transportStdio 通过标准输入输出与本地MCP客户端通信
+	transportStdio = "stdio"
+)
+
 func main() {
-	logger.Info("[启动] 开始启动Rainbond MCP服务器...")
+	transportMode := flag.String("transport", getEnv("RAINBOND_TRANSPORT", transportSSE), "MCP传输方式，可选值：sse/stdio")
+	tokenHeader := flag.String("token-header", getEnv("RAINBOND_TOKEN_HEADER", ""), "除Authorization外额外接受的令牌请求头，例如 X-Rainbond-Token")
+	streamableStateMode := flag.String("state-mode", getEnv("RAINBOND_MCP_STATE_MODE", string(transport.Stateful)), "Streamable HTTP状态模式，可选值：stateful/stateless")
+	toolTimeout := flag.Duration("tool-timeout", getEnvDuration("RAINBOND_TOOL_TIMEOUT", services.DefaultToolTimeout), "工具调用的默认超时时间")
+	watchInterval := flag.Duration("watch-interval", getEnvDuration("RAINBOND_WATCH_INTERVAL", watch.DefaultInterval), "轮询订阅资源状态的间隔")
+	toolTimeoutOverrides := flag.String("tool-timeouts", getEnv("RAINBOND_TOOL_TIMEOUTS", ""), "按工具配置超时时间，格式为 工具名=时长,工具名=时长")
+	flag.Parse()
 
-	// 从环境变量获取配置
-	host := getEnv("RAINBOND_HOST", ":8080") // 使用0.0.0.0允许从任何IP访问，适合Docker环境
-	logger.Info("[配置] RAINBOND_HOST = %s", host)
+	// stdio模式下标准输出用于传输MCP消息，日志必须写到标准错误
+	if *transportMode == transportStdio {
+		logger.SetOutput(os.Stderr)
+	}
+
+	logger.Info("[启动] 开始启动Rainbond MCP服务器...")
+	logger.Info("[配置] transport = %s", *transportMode)
 
 	rainbondAPI := getEnv("RAINBOND_API", "https://rainbond-api.example.com")
 	logger.Info("[配置] RAINBOND_API = %s", rainbondAPI)
 
-	// 创建SSE服务器传输
-	logger.Info("[初始化] 创建SSE服务器传输...")
-	messageEndpointURL := "/message"
-	rainTokenKey := "rainbond_token"
-	paramKeysOpt := transport.WithSSEServerTransportAndHandlerOptionCopyParamKeys([]string{rainTokenKey})
-	transportServer, mcpHandler, err := transport.NewSSEServerTransportAndHandler(messageEndpointURL, paramKeysOpt)
+	// 初始化服务
+	logger.Info("[初始化] 创建服务管理器...")
+	serviceManager := services.NewManager(rainbondAPI)
+	logger.Info("[初始化] 服务管理器创建成功")
 
+	toolTimeouts, err := services.NewToolTimeouts(*toolTimeout, *toolTimeoutOverrides)
 	if err != nil {
-		logger.Fatal("[错误] 创建SSE服务器传输失败: %v", err)
+		logger.Fatal("[错误] 工具超时配置无效: %v", err)
 	}
-	router := http.NewServeMux()
-	router.HandleFunc("/sse", mcpHandler.HandleSSE().ServeHTTP)
-	router.HandleFunc(messageEndpointURL, func(w http.ResponseWriter, r *http.Request) {
-		rainToken := r.URL.Query().Get(rainTokenKey)
-		if rainToken == "" {
-			w.Header().Set("Content-Type", "text/plain")
-			w.WriteHeader(http.StatusBadRequest)
-			if _, e := w.Write([]byte("lack rainbond_token")); e != nil {
-				fmt.Printf("writeError: %+v", e)
-			}
-			return
-		}
-		r = r.WithContext(setRainTokenToCtx(r.Context(), rainToken))
-		mcpHandler.HandleMessage().ServeHTTP(w, r)
-	})
-	httpServer := &http.Server{
-		Addr:        host,
-		Handler:     router,
-		IdleTimeout: time.Minute,
+	logger.Info("[配置] RAINBOND_TOOL_TIMEOUT = %s", toolTimeouts.Default)
+	if *toolTimeoutOverrides != "" {
+		logger.Info("[配置] RAINBOND_TOOL_TIMEOUTS = %s", *toolTimeoutOverrides)
 	}
-	logger.Info("[初始化] SSE服务器传输创建成功")
+	completer := completion.New(serviceManager.APIClients)
+	serviceManager.Watcher.Interval = *watchInterval
+	logger.Info("[配置] RAINBOND_WATCH_INTERVAL = %s", *watchInterval)
 
-	// 创建MCP服务器
-	logger.Info("[初始化] 创建MCP服务器...")
-	mcpServer, err := server.NewServer(
-		transportServer,
-		// 设置服务器信息
-		server.WithServerInfo(protocol.Implementation{
-			Name:    "Rainbond MCP Server",
-			Version: "1.0.0",
-		}),
+	var (
+		transportServers []transport.ServerTransport
+		httpServer       *http.Server
+		toolMiddlewares  = []server.ToolMiddleware{toolTimeouts.Middleware()}
+		// resourceMiddlewares 资源读取的中间件，只有stdio模式需要
+		resourceMiddlewares []tools.ResourceMiddleware
+		// serverOptions 个别传输的MCP服务器需要的额外选项
+		serverOptions = make(map[transport.ServerTransport][]server.Option)
 	)
-	if err != nil {
-		logger.Fatal("[错误] 创建MCP服务器失败: %v", err)
+	switch *transportMode {
+	case transportSSE:
+		// 从环境变量获取配置
+		host := getEnv("RAINBOND_HOST", ":8080") // 使用0.0.0.0允许从任何IP访问，适合Docker环境
+		logger.Info("[配置] RAINBOND_HOST = %s", host)
+
+		stateMode := transport.StateMode(*streamableStateMode)
+		if stateMode != transport.Stateful && stateMode != transport.Stateless {
+			logger.Fatal("[错误] 不支持的Streamable HTTP状态模式: %s，只支持 stateful/stateless", stateMode)
+		}
+		logger.Info("[配置] RAINBOND_MCP_STATE_MODE = %s", stateMode)
+
+		if *tokenHeader != "" {
+			logger.Info("[配置] RAINBOND_TOKEN_HEADER = %s", *tokenHeader)
+		}
+		authenticator := auth.NewAuthenticator(serviceManager.APIClients, *tokenHeader)
+		authenticator.Subscriptions = serviceManager.Watcher
+		authenticator.Completions = completer
+
+		router := http.NewServeMux()
+		sseTransport, err := newSSETransport(router, authenticator)
+		if err != nil {
+			logger.Fatal("[错误] 创建SSE服务器传输失败: %v", err)
+		}
+		streamableTransport, err := newStreamableHTTPTransport(router, authenticator, stateMode)
+		if err != nil {
+			logger.Fatal("[错误] 创建Streamable HTTP服务器传输失败: %v", err)
+		}
+		transportServers = append(transportServers, sseTransport, streamableTransport)
+		if stateMode == transport.Stateless {
+			// 无状态模式没有会话，订阅请求会失败，也无法推送更新通知，因此不声明订阅能力
+			serverOptions[streamableTransport] = []server.Option{server.WithCapabilities(protocol.ServerCapabilities{
+				Prompts:   &protocol.PromptsCapability{ListChanged: true},
+				Resources: &protocol.ResourcesCapability{ListChanged: true},
+				Tools:     &protocol.ToolsCapability{ListChanged: true},
+			})}
+		}
+
+		httpServer = &http.Server{
+			Addr:        host,
+			Handler:     router,
+			IdleTimeout: time.Minute,
+		}
+	case transportStdio:
+		// stdio模式只有一个会话，令牌来自启动进程的环境变量
+		rainToken := os.Getenv("RAINBOND_TOKEN")
+		if rainToken == "" {
+			logger.Fatal("[错误] stdio模式需要设置环境变量 RAINBOND_TOKEN")
+		}
+
+		logger.Info("[初始化] 创建stdio服务器传输...")
+		// stdio传输直接读取标准输入，需要在创建传输之前接管以记录资源订阅
+		if err := serviceManager.Watcher.ObserveStdin("stdio", rainToken); err != nil {
+			logger.Fatal("[错误] 接管标准输入失败: %v", err)
+		}
+		// go-mcp没有实现补全方法，补全请求在交给传输之前直接应答
+		startStdin, err := interceptStdin()
+		if err != nil {
+			logger.Fatal("[错误] 接管标准输入失败: %v", err)
+		}
+		stdioTransport := transport.NewStdioServerTransport()
+		transportServers = append(transportServers, stdioTransport)
+		startStdin(func(line []byte) bool {
+			call, ok := completion.Parse(line)
+			if !ok {
+				return false
+			}
+			// 补全需要查询列表，不阻塞后续消息的读取
+			go func() {
+				ctx, cancel := context.WithTimeout(api.ContextWithToken(context.Background(), rainToken), completion.Timeout)
+				defer cancel()
+				if err := stdioTransport.Send(ctx, "", completer.Reply(ctx, call)); err != nil {
+					logger.Error("[错误] 发送补全响应失败: %v", err)
+				}
+			}()
+			return true
+		})
+		// 工具、资源等所有请求都没有携带令牌，统一把启动进程的令牌写入请求上下文
+		toolMiddlewares = append(toolMiddlewares, services.TokenToolMiddleware(rainToken))
+		resourceMiddlewares = append(resourceMiddlewares, services.TokenResourceMiddleware(rainToken))
+		logger.Info("[初始化] stdio服务器传输创建成功")
+	default:
+		logger.Fatal("[错误] 不支持的传输方式: %s，只支持 sse/stdio", *transportMode)
 	}
-	logger.Info("[初始化] MCP服务器创建成功")
 
-	// 初始化服务
-	logger.Info("[初始化] 创建服务管理器...")
-	serviceManager := services.NewManager(rainbondAPI)
-	logger.Info("[初始化] 服务管理器创建成功")
+	// 每个传输对应一个MCP服务器，共享同一个服务管理器
+	mcpServers := make([]*server.Server, 0, len(transportServers))
+	for _, transportServer := range transportServers {
+		mcpServer, err := newMCPServer(transportServer, serviceManager, toolMiddlewares, resourceMiddlewares, serverOptions[transportServer]...)
+		if err != nil {
+			logger.Fatal("[错误] 创建MCP服务器失败: %v", err)
+		}
+		mcpServers = append(mcpServers, mcpServer)
+		serviceManager.Watcher.AddNotifier(func(ctx context.Context, uri string) error {
+			return mcpServer.SendNotification4ResourcesUpdated(ctx, &protocol.ResourceUpdatedNotification{URI: uri})
+		})
+	}
 
-	// 注册所有工具
-	logger.Info("[初始化] 注册所有工具...")
-	registerTools(mcpServer, serviceManager)
-	logger.Info("[初始化] 所有工具注册完成")
+	// 轮询订阅资源的状态，服务关闭时停止
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go serviceManager.Watcher.Run(watchCtx)
 
 	// 设置优雅关闭
 	logger.Info("[初始化] 设置信号处理...")
@@ -94,22 +188,33 @@ func main() {
 	// 启动服务器
 	logger.Info("[启动] 开始启动服务器...")
 
-	go func() {
-		if err = httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Fatal("[错误] 服务器错误: %v\n", err)
-		}
-	}()
-	go func() {
-		logger.Info("[启动] 服务器开始运行...")
-		if err := mcpServer.Run(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("[错误] 服务器错误: %v\n", err)
-		}
-	}()
+	if httpServer != nil {
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("[错误] 服务器错误: %v\n", err)
+			}
+		}()
+	}
+	runDone := make(chan struct{}, len(mcpServers))
+	for _, mcpServer := range mcpServers {
+		go func(mcpServer *server.Server) {
+			defer func() { runDone <- struct{}{} }()
+			logger.Info("[启动] 服务器开始运行...")
+			if err := mcpServer.Run(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("[错误] 服务器错误: %v\n", err)
+			}
+		}(mcpServer)
+	}
 
-	// 等待关闭信号
+	// 等待关闭信号，stdio模式下客户端关闭标准输入时同样退出
 	logger.Info("[信息] 服务器已启动，等待关闭信号...")
-	<-sigChan
+	select {
+	case <-sigChan:
+	case <-runDone:
+		logger.Info("[关闭] 传输已结束")
+	}
 	logger.Info("[关闭] 正在关闭服务器...")
+	stopWatch()
 
 	// 创建一个带超时的上下文用于关闭
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -117,15 +222,86 @@ func main() {
 
 	// 关闭服务器
 	logger.Info("[关闭] 正在优雅关闭服务器...")
-	if err := mcpServer.Shutdown(ctx); err != nil {
-		logger.Fatal("[错误] 服务器关闭失败: %v\n", err)
+	if httpServer != nil {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logger.Error("[错误] HTTP服务器关闭失败: %v", err)
+		}
+	}
+	for _, mcpServer := range mcpServers {
+		if err := mcpServer.Shutdown(ctx); err != nil {
+			logger.Fatal("[错误] 服务器关闭失败: %v\n", err)
+		}
 	}
 
 	logger.Info("[关闭] 服务器已优雅关闭")
 }
 
+// newMCPServer 基于指定传输创建MCP服务器并注册所有工具、资源和提示词，opts为额外的服务器选项
+func newMCPServer(transportServer transport.ServerTransport, serviceManager *services.Manager, toolMiddlewares []server.ToolMiddleware, resourceMiddlewares []tools.ResourceMiddleware, opts ...server.Option) (*server.Server, error) {
+	logger.Info("[初始化] 创建MCP服务器...")
+	opts = append([]server.Option{
+		// 设置服务器信息
+		server.WithServerInfo(protocol.Implementation{
+			Name:    "Rainbond MCP Server",
+			Version: "1.0.0",
+		}),
+	}, opts...)
+	mcpServer, err := server.NewServer(transportServer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("[初始化] MCP服务器创建成功")
+
+	// 注册所有工具
+	logger.Info("[初始化] 注册所有工具...")
+	registerTools(mcpServer, serviceManager, toolMiddlewares...)
+	logger.Info("[初始化] 所有工具注册完成")
+
+	// 注册资源
+	services.RegisterResources(mcpServer, serviceManager, resourceMiddlewares...)
+
+	// 注册提示词
+	prompts.Register(mcpServer)
+	return mcpServer, nil
+}
+
+// newSSETransport 创建SSE服务器传输，并在路由上挂载 /sse 和 /message
+func newSSETransport(router *http.ServeMux, authenticator *auth.Authenticator) (transport.ServerTransport, error) {
+	logger.Info("[初始化] 创建SSE服务器传输...")
+	messageEndpointURL := "/message"
+	transportServer, mcpHandler, err := transport.NewSSEServerTransportAndHandler(messageEndpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 令牌在建立SSE连接时校验并绑定到会话，消息端点的URL中不再携带令牌
+	router.Handle("/sse", authenticator.SSEHandler(mcpHandler.HandleSSE()))
+	// 被拦截请求的响应需要经过会话的事件流发送
+	send := func(ctx context.Context, sessionID string, msg []byte) error {
+		return transportServer.Send(ctx, sessionID, msg)
+	}
+	router.Handle(messageEndpointURL, authenticator.MessageHandler(mcpHandler.HandleMessage(), send))
+	logger.Info("[初始化] SSE服务器传输创建成功")
+	return transportServer, nil
+}
+
+// newStreamableHTTPTransport 创建Streamable HTTP服务器传输，并在路由上挂载 /mcp
+func newStreamableHTTPTransport(router *http.ServeMux, authenticator *auth.Authenticator, stateMode transport.StateMode) (transport.ServerTransport, error) {
+	logger.Info("[初始化] 创建Streamable HTTP服务器传输...")
+	mcpEndpointURL := "/mcp"
+	stateModeOpt := transport.WithStreamableHTTPServerTransportAndHandlerOptionStateMode(stateMode)
+	transportServer, mcpHandler, err := transport.NewStreamableHTTPServerTransportAndHandler(stateModeOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	router.Handle(mcpEndpointURL, authenticator.StreamableHandler(mcpHandler.HandleMCP()))
+	logger.Info("[初始化] Streamable HTTP服务器传输创建成功")
+	return transportServer, nil
+}
+
 // 注册所有工具
-func registerTools(mcpServer *server.Server, serviceManager *services.Manager) {
+func registerTools(mcpServer *server.Server, serviceManager *services.Manager, middlewares ...server.ToolMiddleware) {
 	// 检查服务管理器
 	if serviceManager == nil {
 		logger.Error("[错误] 服务管理器为空，无法注册工具")
@@ -133,16 +309,16 @@ func registerTools(mcpServer *server.Server, serviceManager *services.Manager) {
 	}
 
 	// 注册团队相关工具
-	services.RegisterTeamTools(mcpServer, serviceManager)
+	services.RegisterTeamTools(mcpServer, serviceManager, middlewares...)
 
 	// 注册集群相关工具
-	services.RegisterRegionTools(mcpServer, serviceManager)
+	services.RegisterRegionTools(mcpServer, serviceManager, middlewares...)
 
 	// 注册应用相关工具
-	services.RegisterAppTools(mcpServer, serviceManager)
+	services.RegisterAppTools(mcpServer, serviceManager, middlewares...)
 
 	// 注册组件相关工具
-	services.RegisterComponentTools(mcpServer, serviceManager)
+	services.RegisterComponentTools(mcpServer, serviceManager, middlewares...)
 
 	logger.Info("[工具] 所有工具注册完成")
 }
@@ -156,6 +332,16 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
-func setRainTokenToCtx(ctx context.Context, rainToken string) context.Context {
-	return context.WithValue(ctx, models.RainTokenKey{}, rainToken)
+// getEnvDuration 获取时长类型的环境变量，不存在或格式错误时返回默认值
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Warn("[配置] 环境变量 %s 的值 %q 不是有效的时长，使用默认值 %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
 }
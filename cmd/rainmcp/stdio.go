@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"rainmcp/pkg/logger"
+)
+
+// interceptStdin 把标准输入替换为管道，stdio传输改为从管道读取消息。必须在创建stdio传输之前调用；
+// 与Watcher.ObserveStdin同时使用时在其之后调用，读取的是它转发后的消息。
+// 调用返回的start后开始转发，handle返回true的消息已被处理，不再转发给传输
+func interceptStdin() (start func(handle func(line []byte) bool), err error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	return func(handle func(line []byte) bool) {
+		go func() {
+			defer writer.Close()
+			lines := bufio.NewReader(stdin)
+			for {
+				line, err := lines.ReadBytes('\n')
+				if len(line) > 0 && !handle(line) {
+					if _, e := writer.Write(line); e != nil {
+						return
+					}
+				}
+				if err != nil {
+					if err != io.EOF {
+						logger.Error("读取标准输入失败: %v", err)
+					}
+					return
+				}
+			}
+		}()
+	}, nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"rainmcp/pkg/logger"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBreakerThreshold 连续失败多少次后熔断
+	defaultBreakerThreshold = 5
+	// defaultBreakerCooldown 熔断后多久放行一个探测请求
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// CircuitOpenError 表示Rainbond控制台处于熔断状态，请求未发出直接失败
+type CircuitOpenError struct {
+	BaseURL string
+	// RetryAfter 距离下一次放行探测请求的时间
+	RetryAfter time.Duration
+}
+
+// Error 实现error接口
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("Rainbond控制台 %s 暂时不可用，已暂停请求，约%s后自动重试", e.BaseURL, e.RetryAfter.Round(time.Second))
+}
+
+// Breaker 按Rainbond地址统计连续失败次数，控制台不可用时快速失败
+type Breaker struct {
+	baseURL   string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// probing 熔断冷却结束后是否已有探测请求在进行
+	probing bool
+}
+
+// NewBreaker 创建熔断器，连续threshold次失败后熔断cooldown时间
+func NewBreaker(baseURL string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{baseURL: baseURL, threshold: threshold, cooldown: cooldown}
+}
+
+// Allow 判断是否允许发出请求，熔断期间返回CircuitOpenError。
+// probe表示本次请求是冷却结束后放行的探测请求，需要原样传给Done
+func (b *Breaker) Allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return false, nil
+	}
+	if remaining := b.cooldown - time.Since(b.openedAt); remaining > 0 {
+		return false, &CircuitOpenError{BaseURL: b.baseURL, RetryAfter: remaining}
+	}
+	// 冷却结束后只放行一个探测请求，其余请求继续快速失败
+	if b.probing {
+		return false, &CircuitOpenError{BaseURL: b.baseURL, RetryAfter: b.cooldown}
+	}
+	b.probing = true
+	return true, nil
+}
+
+// Done 记录一次请求的最终结果，只有控制台不可达类的错误计入失败。
+// 只有探测请求结束时才清除探测状态，熔断前发出、之后才结束的请求不影响探测名额
+func (b *Breaker) Done(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// 调用方取消或超时不代表控制台不可用
+	case isUpstreamFailure(err):
+		b.failures++
+		if b.failures == b.threshold || probe {
+			b.openedAt = time.Now()
+			logger.Warn("Rainbond控制台 %s 连续 %d 次请求失败，熔断 %s", b.baseURL, b.failures, b.cooldown)
+		}
+	default:
+		if b.failures >= b.threshold {
+			logger.Info("Rainbond控制台 %s 已恢复", b.baseURL)
+		}
+		b.failures = 0
+	}
+}
+
+// isUpstreamFailure 判断错误是否说明控制台不可用：网络错误或网关类状态码
+func isUpstreamFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,15 +12,24 @@ import (
 	"time"
 )
 
-// Client 是Rainbond API的客户端
+// DefaultTimeout 调用方未设置截止时间时单个请求的超时时间
+const DefaultTimeout = 30 * time.Second
+
+// Client 是Rainbond API的客户端，每个客户端绑定一个不可变的访问令牌
 type Client struct {
 	BaseURL    string
-	Token      string
+	token      string
 	HTTPClient *http.Client
+	// Timeout 上下文没有截止时间时使用的请求超时时间
+	Timeout time.Duration
+	// Retry 幂等请求的重试策略
+	Retry RetryPolicy
+
+	breaker *Breaker
 }
 
 // NewClient 创建一个新的Rainbond API客户端
-func NewClient(baseURL string) *Client {
+func NewClient(baseURL, token string) *Client {
 	logger.Debug("创建新的API客户端: BaseURL=%s", baseURL)
 
 	// 检查baseURL是否为空
@@ -28,21 +39,23 @@ func NewClient(baseURL string) *Client {
 	}
 
 	return &Client{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		BaseURL:    baseURL,
+		token:      token,
+		HTTPClient: &http.Client{},
+		Timeout:    DefaultTimeout,
+		Retry:      DefaultRetryPolicy,
+		breaker:    NewBreaker(baseURL, defaultBreakerThreshold, defaultBreakerCooldown),
 	}
 }
 
 // Get 发送GET请求到指定的API路径
-func (c *Client) Get(path string) ([]byte, error) {
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
 	logger.Debug("发送GET请求到: %s%s", c.BaseURL, path)
-	return c.Request("GET", path, nil)
+	return c.Request(ctx, "GET", path, nil)
 }
 
 // Post 发送POST请求到指定的API路径
-func (c *Client) Post(path string, data interface{}) ([]byte, error) {
+func (c *Client) Post(ctx context.Context, path string, data interface{}) ([]byte, error) {
 	logger.Debug("发送POST请求到: %s%s", c.BaseURL, path)
 
 	jsonData, err := json.Marshal(data)
@@ -52,11 +65,11 @@ func (c *Client) Post(path string, data interface{}) ([]byte, error) {
 	}
 
 	logger.Debug("POST请求数据: %s", string(jsonData))
-	return c.Request("POST", path, bytes.NewBuffer(jsonData))
+	return c.Request(ctx, "POST", path, bytes.NewBuffer(jsonData))
 }
 
 // Put 发送PUT请求到指定的API路径
-func (c *Client) Put(path string, data interface{}) ([]byte, error) {
+func (c *Client) Put(ctx context.Context, path string, data interface{}) ([]byte, error) {
 	logger.Debug("发送PUT请求到: %s%s", c.BaseURL, path)
 
 	jsonData, err := json.Marshal(data)
@@ -65,36 +78,118 @@ func (c *Client) Put(path string, data interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("序列化PUT数据失败: %v", err)
 	}
 
-	return c.Request("PUT", path, bytes.NewBuffer(jsonData))
+	return c.Request(ctx, "PUT", path, bytes.NewBuffer(jsonData))
 }
 
 // Delete 发送DELETE请求到指定的API路径
-func (c *Client) Delete(path string) ([]byte, error) {
+func (c *Client) Delete(ctx context.Context, path string) ([]byte, error) {
 	logger.Debug("发送DELETE请求到: %s%s", c.BaseURL, path)
-	return c.Request("DELETE", path, nil)
+	return c.Request(ctx, "DELETE", path, nil)
+}
+
+// VerifyToken 通过请求团队列表校验客户端绑定的访问令牌是否有效
+func (c *Client) VerifyToken(ctx context.Context) error {
+	if c.token == "" {
+		return ErrInvalidToken
+	}
+	_, err := c.Get(ctx, "/openapi/v1/mcp/teams")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+		return ErrInvalidToken
+	}
+	return err
 }
 
-// Request 发送请求到指定的API路径
-func (c *Client) Request(method, path string, body io.Reader) ([]byte, error) {
+// Request 发送请求到指定的API路径，请求随ctx取消，ctx没有截止时间时使用客户端的默认超时。
+// 幂等请求遇到网络错误、网关错误或限流时按重试策略退避重试，控制台熔断期间直接失败。
+func (c *Client) Request(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
 	// 验证客户端是否正确初始化
 	if c.BaseURL == "" {
 		logger.Error("BaseURL为空")
 		return nil, fmt.Errorf("API客户端未正确初始化，BaseURL为空")
 	}
 
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	// 请求体需要在重试时重复发送，先完整读出
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			logger.Error("读取请求体失败: %v", err)
+			return nil, fmt.Errorf("读取请求体失败: %v", err)
+		}
+	}
+
 	url := fmt.Sprintf("%s%s", c.BaseURL, path)
+	maxAttempts := 1
+	if isIdempotent(ctx, method) && c.Retry.MaxAttempts > 1 {
+		maxAttempts = c.Retry.MaxAttempts
+	}
+
+	// 一次调用无论重试多少次只占用一次熔断判断，只记录最终结果，避免单次调用的重试被计为多次连续失败
+	probe := false
+	if c.breaker != nil {
+		var err error
+		if probe, err = c.breaker.Allow(); err != nil {
+			logger.Warn("请求被熔断: %s %s, %v", method, url, err)
+			return nil, err
+		}
+	}
+	respBody, err := c.doWithRetry(ctx, method, url, payload, maxAttempts)
+	if c.breaker != nil {
+		c.breaker.Done(probe, err)
+	}
+	return respBody, err
+}
+
+// doWithRetry 发送请求，可重试的错误按重试策略退避后重发，最多尝试maxAttempts次
+func (c *Client) doWithRetry(ctx context.Context, method, url string, payload []byte, maxAttempts int) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		respBody, err := c.do(ctx, method, url, payload)
+		if attempt >= maxAttempts || !isRetryable(err) {
+			return respBody, err
+		}
+
+		delay := c.Retry.delay(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// 剩余时间不够再等一次，直接返回本次错误
+			return nil, err
+		}
+		logger.Warn("请求失败，%s后进行第 %d 次尝试: %s %s, 错误: %v", delay, attempt+1, method, url, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("请求已中止: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// do 发送一次HTTP请求，状态码大于等于400时返回APIError
+func (c *Client) do(ctx context.Context, method, url string, payload []byte) ([]byte, error) {
 	logger.Debug("发送 %s 请求到: %s", method, url)
 
-	req, err := http.NewRequest(method, url, body)
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		logger.Error("创建请求失败: %v", err)
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
+	if c.token != "" {
 		logger.Debug("添加授权头")
-		req.Header.Set("Authorization", c.Token)
+		req.Header.Set("Authorization", c.token)
 	} else {
 		logger.Warn("未设置访问令牌")
 	}
@@ -104,6 +199,10 @@ func (c *Client) Request(method, path string, body io.Reader) ([]byte, error) {
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			logger.Warn("请求已中止: %s %s, 原因: %v", method, url, ctxErr)
+			return nil, fmt.Errorf("请求已中止: %w", ctxErr)
+		}
 		logger.Error("请求失败: %v", err)
 		return nil, fmt.Errorf("请求失败: %v", err)
 	}
@@ -121,7 +220,9 @@ func (c *Client) Request(method, path string, body io.Reader) ([]byte, error) {
 
 	if resp.StatusCode >= 400 {
 		logger.Error("请求失败: 状态码=%d, 响应=%s", resp.StatusCode, string(respBody))
-		return nil, fmt.Errorf("API错误: %s, 状态码: %d", string(respBody), resp.StatusCode)
+		apiErr := newAPIError(resp.StatusCode, respBody)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, apiErr
 	}
 
 	logger.Debug("请求成功: 状态码=%d", resp.StatusCode)
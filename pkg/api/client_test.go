@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRequestRetriesIdempotent 验证GET请求在503后按Retry-After重试，非幂等的POST不重试
+func TestRequestRetriesIdempotent(t *testing.T) {
+	var calls int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":200}`))
+	}))
+	defer stub.Close()
+
+	client := NewClient(stub.URL, "token")
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	if _, err := client.Get(context.Background(), "/"); err != nil {
+		t.Fatalf("重试后应成功: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("GET应请求2次，实际 %d 次", got)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	var apiErr *APIError
+	if _, err := client.Post(context.Background(), "/", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("POST应直接返回503错误: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("POST不应重试，实际请求 %d 次", got)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if _, err := client.Post(ContextWithIdempotent(context.Background()), "/", nil); err != nil {
+		t.Fatalf("声明幂等的POST重试后应成功: %v", err)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if _, err := client.Put(context.Background(), "/", nil); err != nil {
+		t.Fatalf("PUT重试后应成功: %v", err)
+	}
+}
+
+// TestBreakerFailsFast 验证连续失败后熔断，冷却结束后探测成功即恢复
+func TestBreakerFailsFast(t *testing.T) {
+	var healthy int32
+	var calls int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"code":200}`))
+	}))
+	defer stub.Close()
+
+	client := NewClient(stub.URL, "token")
+	client.Retry = RetryPolicy{MaxAttempts: 1}
+	client.breaker = NewBreaker(stub.URL, 2, 50*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), "/"); err == nil {
+			t.Fatal("控制台返回502时应失败")
+		}
+	}
+	var openErr *CircuitOpenError
+	if _, err := client.Get(context.Background(), "/"); !errors.As(err, &openErr) {
+		t.Fatalf("连续失败后应熔断: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("熔断期间不应发出请求，实际请求 %d 次", got)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Get(context.Background(), "/"); err != nil {
+		t.Fatalf("冷却结束后探测请求应成功: %v", err)
+	}
+	if _, err := client.Get(context.Background(), "/"); err != nil {
+		t.Fatalf("恢复后请求应成功: %v", err)
+	}
+}
+
+// TestBreakerCountsRetriedCallOnce 验证一次调用重试多次失败只计为一次熔断失败
+func TestBreakerCountsRetriedCallOnce(t *testing.T) {
+	var calls int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer stub.Close()
+
+	client := NewClient(stub.URL, "token")
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	client.breaker = NewBreaker(stub.URL, 2, time.Minute)
+
+	if _, err := client.Get(context.Background(), "/"); err == nil {
+		t.Fatal("控制台返回502时应失败")
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("应重试到3次，实际请求 %d 次", got)
+	}
+	var openErr *CircuitOpenError
+	if _, err := client.Get(context.Background(), "/"); errors.As(err, &openErr) {
+		t.Fatal("一次重试失败的调用不应被计为多次失败而熔断")
+	}
+	if _, err := client.Get(context.Background(), "/"); !errors.As(err, &openErr) {
+		t.Fatalf("连续两次调用失败后应熔断: %v", err)
+	}
+}
+
+// TestBreakerProbeOnly 验证熔断前发出、冷却结束后才完成的请求不会释放探测名额
+func TestBreakerProbeOnly(t *testing.T) {
+	b := NewBreaker("http://rainbond", 1, 10*time.Millisecond)
+	stale, err := b.Allow()
+	if err != nil || stale {
+		t.Fatalf("熔断前的请求应放行且不是探测请求: probe=%v err=%v", stale, err)
+	}
+	b.Done(false, errors.New("connection refused"))
+	time.Sleep(20 * time.Millisecond)
+
+	probe, err := b.Allow()
+	if err != nil || !probe {
+		t.Fatalf("冷却结束后应放行探测请求: probe=%v err=%v", probe, err)
+	}
+	// 熔断前发出的另一个请求此时才失败结束
+	b.Done(stale, errors.New("connection refused"))
+	var openErr *CircuitOpenError
+	if _, err := b.Allow(); !errors.As(err, &openErr) {
+		t.Fatalf("探测请求结束前应继续快速失败: %v", err)
+	}
+	b.Done(probe, nil)
+	if _, err := b.Allow(); err != nil {
+		t.Fatalf("探测成功后应恢复: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken 表示Rainbond拒绝了访问令牌
+var ErrInvalidToken = errors.New("Rainbond访问令牌无效或已过期")
+
+// ErrorKind 表示Rainbond API错误的分类
+type ErrorKind string
+
+const (
+	// ErrorKindAuth 令牌无效、过期或没有权限
+	ErrorKindAuth ErrorKind = "auth"
+	// ErrorKindNotFound 团队、集群、应用或组件不存在
+	ErrorKindNotFound ErrorKind = "not_found"
+	// ErrorKindConflict 资源已存在或状态冲突
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindQuota 超出团队或集群的资源配额
+	ErrorKindQuota ErrorKind = "quota"
+	// ErrorKindValidation 请求参数不合法
+	ErrorKindValidation ErrorKind = "validation"
+	// ErrorKindServer Rainbond服务端错误
+	ErrorKindServer ErrorKind = "server"
+	// ErrorKindUnknown 无法归类的错误
+	ErrorKindUnknown ErrorKind = "unknown"
+)
+
+// quotaKeywords 出现在错误信息中时判定为配额不足。只使用明确指向配额和资源的词组，
+// 避免“长度超过限制”“参数不足”这类参数错误被误判为配额不足
+var quotaKeywords = []string{"quota", "lack_of", "lack of", "insufficient", "over_max", "not enough resource", "配额不足", "资源不足", "超出配额", "超过配额", "内存不足"}
+
+// maxBodyInMessage 错误信息中保留的原始响应体最大长度
+const maxBodyInMessage = 512
+
+// APIError 表示Rainbond API返回的错误响应，Code、Msg和MsgShow来自响应体的统一结构
+type APIError struct {
+	StatusCode int
+	Code       int
+	Msg        string
+	MsgShow    string
+	Body       string
+	// RetryAfter 来自Retry-After响应头的建议等待时间
+	RetryAfter time.Duration
+}
+
+// newAPIError 根据状态码和响应体创建APIError，响应体不是统一结构时只保留原文
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: string(body)}
+
+	var envelope struct {
+		Code    interface{} `json:"code"`
+		Msg     string      `json:"msg"`
+		MsgShow string      `json:"msg_show"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return apiErr
+	}
+	switch code := envelope.Code.(type) {
+	case float64:
+		apiErr.Code = int(code)
+	case string:
+		apiErr.Code, _ = strconv.Atoi(code)
+	}
+	apiErr.Msg = envelope.Msg
+	apiErr.MsgShow = envelope.MsgShow
+	return apiErr
+}
+
+// CheckEnvelope 检查HTTP状态码正常但响应体中业务错误码表示失败的情况，失败时返回APIError
+func CheckEnvelope(body []byte) error {
+	apiErr := newAPIError(http.StatusOK, body)
+	if apiErr.Code < http.StatusBadRequest {
+		return nil
+	}
+	apiErr.StatusCode = apiErr.Code
+	return apiErr
+}
+
+// Error 实现error接口
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API错误: %s, 状态码: %d", e.Message(), e.StatusCode)
+}
+
+// Message 返回面向用户的错误信息，依次使用msg_show、msg和原始响应体
+func (e *APIError) Message() string {
+	if e.MsgShow != "" {
+		return e.MsgShow
+	}
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if body := strings.TrimSpace(e.Body); body != "" {
+		if len(body) > maxBodyInMessage {
+			body = body[:maxBodyInMessage] + "..."
+		}
+		return body
+	}
+	return http.StatusText(e.StatusCode)
+}
+
+// IsUnauthorized 判断是否为认证失败
+func (e *APIError) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// Kind 根据状态码和错误信息对错误进行分类
+func (e *APIError) Kind() ErrorKind {
+	switch {
+	case e.IsUnauthorized():
+		return ErrorKindAuth
+	case e.StatusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrorKindConflict
+	case e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusPaymentRequired:
+		return ErrorKindQuota
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrorKindServer
+	case e.StatusCode >= http.StatusBadRequest:
+		// Rainbond的配额错误通常以400/412返回，只能通过错误信息识别
+		text := strings.ToLower(e.Msg + " " + e.MsgShow)
+		for _, keyword := range quotaKeywords {
+			if strings.Contains(text, keyword) {
+				return ErrorKindQuota
+			}
+		}
+		return ErrorKindValidation
+	}
+	return ErrorKindUnknown
+}
+
+// Hint 返回针对错误分类的处理建议
+func (e *APIError) Hint() string {
+	switch e.Kind() {
+	case ErrorKindAuth:
+		return "访问令牌无效、已过期或没有该资源的权限，请检查令牌或联系团队管理员授权"
+	case ErrorKindNotFound:
+		return "资源不存在，请先调用列表类工具确认团队、集群、应用或组件的标识是否正确"
+	case ErrorKindConflict:
+		return "资源已存在或当前状态不允许该操作，请查询最新状态后再决定是否重试"
+	case ErrorKindQuota:
+		if e.StatusCode == http.StatusTooManyRequests {
+			return "请求过于频繁，请稍后重试"
+		}
+		return "超出团队或集群的资源配额，请释放不再使用的资源或联系管理员调整配额"
+	case ErrorKindValidation:
+		return "请求参数不合法，请根据错误信息修正参数后重试"
+	case ErrorKindServer:
+		return "Rainbond服务端异常，请稍后重试，持续失败时请联系平台管理员"
+	}
+	return "请根据错误信息检查请求后重试"
+}
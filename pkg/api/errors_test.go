@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestErrorKindQuota 验证配额错误按状态码和错误信息识别，普通参数错误不被误判为配额不足
+func TestErrorKindQuota(t *testing.T) {
+	cases := []struct {
+		status int
+		msg    string
+		want   ErrorKind
+	}{
+		{http.StatusBadRequest, "团队配额不足", ErrorKindQuota},
+		{http.StatusPreconditionFailed, "集群资源不足，无法创建组件", ErrorKindQuota},
+		{http.StatusBadRequest, "内存申请超出配额", ErrorKindQuota},
+		{http.StatusBadRequest, "tenant quota exceeded", ErrorKindQuota},
+		{http.StatusPreconditionFailed, "lack_of_memory", ErrorKindQuota},
+		{http.StatusBadRequest, "Insufficient cpu", ErrorKindQuota},
+		{http.StatusTooManyRequests, "", ErrorKindQuota},
+		{http.StatusBadRequest, "组件名称长度超过32个字符", ErrorKindValidation},
+		{http.StatusBadRequest, "参数不足", ErrorKindValidation},
+		{http.StatusBadRequest, "端口号超出范围", ErrorKindValidation},
+		{http.StatusBadRequest, "not enough arguments", ErrorKindValidation},
+		{http.StatusInternalServerError, "资源不足", ErrorKindServer},
+	}
+	for _, c := range cases {
+		err := &APIError{StatusCode: c.status, Msg: c.msg}
+		if got := err.Kind(); got != c.want {
+			t.Errorf("Kind(%d, %q) = %s，期望 %s", c.status, c.msg, got, c.want)
+		}
+	}
+}
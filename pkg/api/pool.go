@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultClientIdleTTL 客户端在池中的默认最长空闲时间
+const DefaultClientIdleTTL = 30 * time.Minute
+
+// pooledClient 池中的客户端及其最近一次被取用的时间
+type pooledClient struct {
+	client *Client
+	// lastUsed 最近一次被取用的时间(UnixNano)，读锁下原子更新
+	lastUsed int64
+}
+
+// Pool 按访问令牌维护API客户端，保证不同会话的凭证互不干扰
+type Pool struct {
+	BaseURL string
+	// Timeout 池内客户端在上下文没有截止时间时使用的请求超时时间
+	Timeout time.Duration
+	// Retry 池内客户端的重试策略
+	Retry RetryPolicy
+	// IdleTTL 客户端超过该时间没有被取用时从池中移除，下次使用时重新创建；小于等于0时不移除
+	IdleTTL time.Duration
+
+	httpClient *http.Client
+	// breaker 池内客户端访问同一个Rainbond地址，共享一个熔断器
+	breaker *Breaker
+
+	mu      sync.RWMutex
+	clients map[string]*pooledClient
+	// lastSweep 上一次清理空闲客户端的时间
+	lastSweep time.Time
+}
+
+// NewPool 创建一个新的API客户端池，池内所有客户端共享同一个HTTP连接池
+func NewPool(baseURL string) *Pool {
+	logger.Debug("创建新的API客户端池: BaseURL=%s", baseURL)
+
+	// 检查baseURL是否为空
+	if baseURL == "" {
+		logger.Warn("BaseURL为空")
+		baseURL = "https://rainbond-api.example.com" // 设置一个默认值以避免空指针
+	}
+
+	// 超时由每次调用的上下文控制，HTTP客户端本身不设置固定超时
+	return &Pool{
+		BaseURL:    baseURL,
+		Timeout:    DefaultTimeout,
+		Retry:      DefaultRetryPolicy,
+		IdleTTL:    DefaultClientIdleTTL,
+		httpClient: &http.Client{},
+		breaker:    NewBreaker(baseURL, defaultBreakerThreshold, defaultBreakerCooldown),
+		clients:    make(map[string]*pooledClient),
+		lastSweep:  time.Now(),
+	}
+}
+
+// Get 获取绑定指定令牌的API客户端，不存在时创建
+func (p *Pool) Get(token string) *Client {
+	now := time.Now()
+	p.mu.RLock()
+	entry, ok := p.clients[token]
+	if ok {
+		atomic.StoreInt64(&entry.lastUsed, now.UnixNano())
+	}
+	p.mu.RUnlock()
+	if ok {
+		return entry.client
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entry, ok = p.clients[token]; ok {
+		atomic.StoreInt64(&entry.lastUsed, now.UnixNano())
+		return entry.client
+	}
+	// 池只在新增客户端时增长，此时顺便清理空闲的客户端
+	p.sweep(now)
+	client := &Client{
+		BaseURL:    p.BaseURL,
+		token:      token,
+		HTTPClient: p.httpClient,
+		Timeout:    p.Timeout,
+		Retry:      p.Retry,
+		breaker:    p.breaker,
+	}
+	p.clients[token] = &pooledClient{client: client, lastUsed: now.UnixNano()}
+	logger.Debug("API客户端池新增客户端，当前数量: %d", len(p.clients))
+	return client
+}
+
+// sweep 移除空闲超过IdleTTL的客户端，两次清理至少间隔IdleTTL的一半，调用方需持有写锁。
+// 被移除的客户端仍可以继续完成进行中的请求
+func (p *Pool) sweep(now time.Time) {
+	if p.IdleTTL <= 0 || now.Sub(p.lastSweep) < p.IdleTTL/2 {
+		return
+	}
+	p.lastSweep = now
+	before := len(p.clients)
+	for token, entry := range p.clients {
+		if now.Sub(time.Unix(0, atomic.LoadInt64(&entry.lastUsed))) > p.IdleTTL {
+			delete(p.clients, token)
+		}
+	}
+	if removed := before - len(p.clients); removed > 0 {
+		logger.Debug("API客户端池移除 %d 个空闲客户端，当前数量: %d", removed, len(p.clients))
+	}
+}
+
+// FromContext 根据上下文中的访问令牌获取API客户端
+func (p *Pool) FromContext(ctx context.Context) (*Client, error) {
+	token, err := TokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(token), nil
+}
+
+// Remove 从池中移除指定令牌的客户端
+func (p *Pool) Remove(token string) {
+	p.mu.Lock()
+	delete(p.clients, token)
+	p.mu.Unlock()
+}
+
+// ContextWithToken 将Rainbond访问令牌写入上下文
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, models.RainTokenKey{}, token)
+}
+
+// ContextWithDisconnect 记录MCP客户端断开连接的信号，信号关闭时进行中的工具调用应当中止
+func ContextWithDisconnect(ctx context.Context, done <-chan struct{}) context.Context {
+	return context.WithValue(ctx, disconnectKey{}, done)
+}
+
+// DisconnectFromContext 读取MCP客户端断开连接的信号，没有时返回nil
+func DisconnectFromContext(ctx context.Context) <-chan struct{} {
+	done, _ := ctx.Value(disconnectKey{}).(<-chan struct{})
+	return done
+}
+
+type disconnectKey struct{}
+
+// TokenFromContext 从上下文中读取Rainbond访问令牌
+func TokenFromContext(ctx context.Context) (string, error) {
+	token, _ := ctx.Value(models.RainTokenKey{}).(string)
+	if token == "" {
+		return "", fmt.Errorf("缺少Rainbond访问令牌")
+	}
+	return token, nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPoolEvictsIdleClients 验证长时间未使用的令牌的客户端在新增客户端时被移除，仍在使用的保留
+func TestPoolEvictsIdleClients(t *testing.T) {
+	pool := NewPool("http://rainbond")
+	pool.IdleTTL = 40 * time.Millisecond
+
+	idle := pool.Get("idle")
+	active := pool.Get("active")
+	for i := 0; i < 5; i++ {
+		time.Sleep(10 * time.Millisecond)
+		pool.Get("active")
+	}
+	pool.Get("new")
+
+	pool.mu.RLock()
+	_, idleKept := pool.clients["idle"]
+	size := len(pool.clients)
+	pool.mu.RUnlock()
+	if idleKept || size != 2 {
+		t.Fatalf("空闲客户端应被移除，当前数量 %d", size)
+	}
+	if pool.Get("active") != active {
+		t.Fatal("仍在使用的客户端不应被替换")
+	}
+	if pool.Get("idle") == idle {
+		t.Fatal("被移除的令牌再次使用时应创建新客户端")
+	}
+}
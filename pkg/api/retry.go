@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 描述幂等请求的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 包含首次请求在内的最大尝试次数，小于等于1时不重试
+	MaxAttempts int
+	// BaseDelay 首次重试前的基础等待时间，之后按指数增长
+	BaseDelay time.Duration
+	// MaxDelay 单次等待时间的上限，同样约束Retry-After
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy 默认重试策略：最多尝试3次，等待时间从200ms开始翻倍，不超过5s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// ContextWithIdempotent 声明本次POST请求是幂等的，失败后可以安全重试，例如请求体是目标值而不是增量的操作。
+// GET、PUT、DELETE按HTTP语义默认视为幂等
+func ContextWithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+type idempotentKey struct{}
+
+// isIdempotent 判断请求是否允许重试
+func isIdempotent(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	idempotent, _ := ctx.Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// backoff 返回第attempt次失败后的等待时间，使用带抖动的指数退避
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	// 在[delay/2, delay]之间随机，避免多个会话同时重试
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// delay 返回下一次重试前的等待时间，优先使用服务端返回的Retry-After
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return apiErr.RetryAfter
+	}
+	return p.backoff(attempt)
+}
+
+// isRetryable 判断错误是否值得重试：网络错误，以及网关错误、服务不可用和限流
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// parseRetryAfter 解析Retry-After响应头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,362 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TokenQueryKey 旧客户端使用的令牌查询参数名，令牌会出现在代理访问日志中，已不再接受
+	TokenQueryKey = "rainbond_token"
+
+	// sseSessionQueryKey SSE消息端点携带会话ID的查询参数名
+	sseSessionQueryKey = "sessionID"
+	// streamableSessionHeader Streamable HTTP携带会话ID的请求头
+	streamableSessionHeader = "Mcp-Session-Id"
+
+	// verifiedTTL 令牌校验结果的缓存时间
+	verifiedTTL = 5 * time.Minute
+	// sessionIdleTTL 会话令牌绑定的最长空闲时间
+	sessionIdleTTL = 24 * time.Hour
+)
+
+// SubscriptionObserver 接收会话发来的消息以记录资源订阅，会话结束时清除订阅
+type SubscriptionObserver interface {
+	Observe(sessionID, token string, msg []byte)
+	Forget(sessionID string)
+}
+
+// Interceptor 直接应答MCP服务器不支持的请求，返回响应消息；不处理的消息返回false
+type Interceptor interface {
+	Intercept(ctx context.Context, msg []byte) ([]byte, bool)
+}
+
+// maxObservedBody 检查订阅和补全消息时读取的最大请求体，这类请求很小，超过的请求不检查
+const maxObservedBody = 64 << 10
+
+// replyFunc 把拦截请求的响应发回客户端。SSE的响应需要经过事件流，Streamable HTTP直接写在POST响应中
+type replyFunc func(w http.ResponseWriter, r *http.Request, sessionID string, msg []byte)
+
+// Authenticator 负责从HTTP请求中提取令牌、校验令牌并把令牌绑定到MCP会话
+type Authenticator struct {
+	// Subscriptions 为空时不记录资源订阅
+	Subscriptions SubscriptionObserver
+	// Completions 为空时补全请求交给MCP服务器，按不支持的方法返回错误
+	Completions Interceptor
+
+	clients *api.Pool
+	header  string
+	idleTTL time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*binding
+	verified map[string]time.Time
+}
+
+// binding 记录会话绑定的令牌和最近一次使用时间，done在会话结束时关闭
+type binding struct {
+	token    string
+	lastUsed time.Time
+	done     chan struct{}
+}
+
+// NewAuthenticator 创建认证器，header为额外接受的自定义令牌请求头，为空时只接受Authorization
+func NewAuthenticator(clients *api.Pool, header string) *Authenticator {
+	logger.Debug("创建认证器: header=%s", header)
+	return &Authenticator{
+		clients:  clients,
+		header:   header,
+		idleTTL:  sessionIdleTTL,
+		sessions: make(map[string]*binding),
+		verified: make(map[string]time.Time),
+	}
+}
+
+// SSEHandler 包装 /sse 端点：建立连接前校验令牌，连接断开后解除会话绑定
+func (a *Authenticator) SSEHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := a.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		// 会话ID由传输层在endpoint事件中下发，写回客户端之前先完成绑定
+		recorder := &sseSessionRecorder{ResponseWriter: w, bind: func(sessionID string) { a.bind(sessionID, token) }}
+		defer func() {
+			if recorder.sessionID != "" {
+				a.unbind(recorder.sessionID)
+			}
+		}()
+		next.ServeHTTP(recorder, r.WithContext(api.ContextWithToken(r.Context(), token)))
+	})
+}
+
+// MessageHandler 包装 /message 端点：请求携带的令牌必须与会话绑定的令牌一致。
+// send为SSE传输的发送函数，被拦截请求的响应通过它写入会话的事件流
+func (a *Authenticator) MessageHandler(next http.Handler, send func(ctx context.Context, sessionID string, msg []byte) error) http.Handler {
+	reply := func(w http.ResponseWriter, r *http.Request, sessionID string, msg []byte) {
+		if err := send(r.Context(), sessionID, msg); err != nil {
+			logger.Error("发送会话 %s 的响应失败: %v", sessionID, err)
+			writeError(w, http.StatusInternalServerError, "send response failed")
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.serveWithSession(w, r, r.URL.Query().Get(sseSessionQueryKey), next, reply)
+	})
+}
+
+// StreamableHandler 包装 /mcp 端点：有会话时请求携带的令牌必须与绑定的一致，否则校验请求携带的令牌
+func (a *Authenticator) StreamableHandler(next http.Handler) http.Handler {
+	// 工具调用的响应写在同一个POST请求上，请求断开即视为客户端断开
+	next = withRequestDisconnect(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(streamableSessionHeader)
+		if sessionID != "" {
+			if r.Method == http.MethodDelete {
+				defer a.unbind(sessionID)
+			}
+			a.serveWithSession(w, r, sessionID, next, replyJSON)
+			return
+		}
+
+		// 有状态模式下初始化响应会在请求头中返回新会话ID
+		token, ok := a.authenticate(w, r)
+		if !ok {
+			return
+		}
+		r = r.WithContext(api.ContextWithToken(r.Context(), token))
+		if a.intercept(w, r, "", token, replyJSON) {
+			return
+		}
+		recorder := &headerSessionRecorder{ResponseWriter: w, bind: func(sessionID string) { a.bind(sessionID, token) }}
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// serveWithSession 处理携带会话ID的请求。会话ID会出现在URL和访问日志中，不能代替令牌，
+// 每个请求都必须携带令牌：会话已绑定时令牌必须与绑定的一致，未绑定时按普通请求校验
+func (a *Authenticator) serveWithSession(w http.ResponseWriter, r *http.Request, sessionID string, next http.Handler, reply replyFunc) {
+	if rejectQueryToken(w, r) {
+		return
+	}
+	ctx := r.Context()
+	token, done, ok := a.sessionToken(sessionID)
+	if ok {
+		presented := a.extractToken(r)
+		if presented == "" {
+			writeError(w, http.StatusUnauthorized, "lack rainbond token")
+			return
+		}
+		if presented != token {
+			logger.Warn("会话 %s 的请求携带的令牌与绑定的令牌不一致", sessionID)
+			writeError(w, http.StatusForbidden, "token does not match the session")
+			return
+		}
+		// 消息请求本身很快返回，工具调用需要跟随会话连接的生命周期
+		ctx = api.ContextWithDisconnect(ctx, done)
+	} else {
+		if token, ok = a.authenticate(w, r); !ok {
+			return
+		}
+	}
+	r = r.WithContext(api.ContextWithToken(ctx, token))
+	if a.intercept(w, r, sessionID, token, reply) {
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// intercept 把POST消息交给订阅记录器和补全拦截器，返回true表示请求已应答。
+// 读取后的请求体放回请求中，交给MCP传输继续处理
+func (a *Authenticator) intercept(w http.ResponseWriter, r *http.Request, sessionID, token string, reply replyFunc) bool {
+	observe := a.Subscriptions != nil && sessionID != ""
+	if r.Method != http.MethodPost || (!observe && a.Completions == nil) || r.ContentLength > maxObservedBody {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxObservedBody+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil || len(body) > maxObservedBody {
+		return false
+	}
+	if observe {
+		a.Subscriptions.Observe(sessionID, token, body)
+	}
+	if a.Completions == nil {
+		return false
+	}
+	msg, ok := a.Completions.Intercept(r.Context(), body)
+	if ok {
+		reply(w, r, sessionID, msg)
+	}
+	return ok
+}
+
+// replyJSON 以JSON响应体应答Streamable HTTP请求
+func replyJSON(w http.ResponseWriter, _ *http.Request, _ string, msg []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(msg); err != nil {
+		logger.Error("写入响应失败: %v", err)
+	}
+}
+
+// withRequestDisconnect 把请求自身的结束信号作为客户端断开信号
+func withRequestDisconnect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(api.ContextWithDisconnect(r.Context(), r.Context().Done())))
+	})
+}
+
+// authenticate 提取并校验请求携带的令牌，失败时直接写回错误响应
+func (a *Authenticator) authenticate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if rejectQueryToken(w, r) {
+		return "", false
+	}
+	token := a.extractToken(r)
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "lack rainbond token")
+		return "", false
+	}
+
+	if err := a.verify(r.Context(), token); err != nil {
+		if errors.Is(err, api.ErrInvalidToken) {
+			logger.Warn("令牌校验失败: %v", err)
+			writeError(w, http.StatusUnauthorized, "invalid rainbond token")
+			return "", false
+		}
+		logger.Error("令牌校验请求失败: %v", err)
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("verify rainbond token failed: %v", err))
+		return "", false
+	}
+	return token, true
+}
+
+// rejectQueryToken 拒绝通过查询参数携带令牌的请求，返回true表示已写回错误响应。
+// 日志中只记录参数名，不记录令牌
+func rejectQueryToken(w http.ResponseWriter, r *http.Request) bool {
+	if !r.URL.Query().Has(TokenQueryKey) {
+		return false
+	}
+	logger.Warn("拒绝通过查询参数 %s 携带令牌的请求: %s %s", TokenQueryKey, r.Method, r.URL.Path)
+	writeError(w, http.StatusUnauthorized, TokenQueryKey+" query parameter is no longer supported, send the token in the Authorization header")
+	return true
+}
+
+// extractToken 依次从Authorization和自定义请求头中读取令牌，Authorization的认证方案不区分大小写
+func (a *Authenticator) extractToken(r *http.Request) string {
+	if value := r.Header.Get("Authorization"); value != "" {
+		token := value
+		if scheme, credentials, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			token = credentials
+		}
+		if token = strings.TrimSpace(token); token != "" {
+			return token
+		}
+	}
+	if a.header != "" {
+		if token := strings.TrimSpace(r.Header.Get(a.header)); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// verify 校验令牌，有效结果在verifiedTTL内复用
+func (a *Authenticator) verify(ctx context.Context, token string) error {
+	a.mu.Lock()
+	verifiedAt, ok := a.verified[token]
+	a.mu.Unlock()
+	if ok && time.Since(verifiedAt) < verifiedTTL {
+		return nil
+	}
+
+	if err := a.clients.Get(token).VerifyToken(ctx); err != nil {
+		if errors.Is(err, api.ErrInvalidToken) {
+			a.clients.Remove(token)
+		}
+		return err
+	}
+
+	now := time.Now()
+	a.mu.Lock()
+	for t, verifiedAt := range a.verified {
+		if now.Sub(verifiedAt) >= verifiedTTL {
+			delete(a.verified, t)
+		}
+	}
+	a.verified[token] = now
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *Authenticator) bind(sessionID, token string) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// 顺带清理长期未使用的绑定，避免未显式关闭的会话占用内存
+	for id, b := range a.sessions {
+		if now.Sub(b.lastUsed) > a.idleTTL {
+			delete(a.sessions, id)
+			close(b.done)
+		}
+	}
+	a.sessions[sessionID] = &binding{token: token, lastUsed: now, done: make(chan struct{})}
+	logger.Debug("会话 %s 已绑定令牌，当前会话数: %d", sessionID, len(a.sessions))
+}
+
+func (a *Authenticator) unbind(sessionID string) {
+	a.mu.Lock()
+	if b, ok := a.sessions[sessionID]; ok {
+		delete(a.sessions, sessionID)
+		close(b.done)
+	}
+	a.mu.Unlock()
+	if a.Subscriptions != nil {
+		a.Subscriptions.Forget(sessionID)
+	}
+	logger.Debug("会话 %s 已解除令牌绑定", sessionID)
+}
+
+func (a *Authenticator) sessionToken(sessionID string) (string, <-chan struct{}, bool) {
+	if sessionID == "" {
+		return "", nil, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b, ok := a.sessions[sessionID]
+	if !ok {
+		return "", nil, false
+	}
+	// 超过空闲时间的绑定视为已失效，请求需要重新校验令牌
+	now := time.Now()
+	if now.Sub(b.lastUsed) > a.idleTTL {
+		delete(a.sessions, sessionID)
+		close(b.done)
+		return "", nil, false
+	}
+	b.lastUsed = now
+	return b.token, b.done, true
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "text/plain")
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="rainbond"`)
+	}
+	w.WriteHeader(code)
+	if _, e := w.Write([]byte(message)); e != nil {
+		logger.Error("写入错误响应失败: %v", e)
+	}
+}
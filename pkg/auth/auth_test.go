@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"rainmcp/pkg/api"
+)
+
+// newTestAuthenticator 创建使用桩API的认证器，桩API只接受令牌good
+func newTestAuthenticator(t *testing.T, header string) *Authenticator {
+	t.Helper()
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"msg":"unauthorized"}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"data":{"list":[]}}`))
+	}))
+	t.Cleanup(stub.Close)
+	return NewAuthenticator(api.NewPool(stub.URL), header)
+}
+
+// TestExtractToken 验证令牌的读取顺序和Bearer方案的大小写
+func TestExtractToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		headers map[string]string
+		want    string
+	}{
+		{"Bearer方案", "", map[string]string{"Authorization": "Bearer abc"}, "abc"},
+		{"小写bearer", "", map[string]string{"Authorization": "bearer abc"}, "abc"},
+		{"大写BEARER", "", map[string]string{"Authorization": "BEARER abc"}, "abc"},
+		{"不带方案", "", map[string]string{"Authorization": "abc"}, "abc"},
+		{"只有方案", "", map[string]string{"Authorization": "Bearer  "}, ""},
+		{"自定义请求头", "X-Rainbond-Token", map[string]string{"X-Rainbond-Token": " abc "}, "abc"},
+		{"Authorization优先", "X-Rainbond-Token", map[string]string{"Authorization": "Bearer a", "X-Rainbond-Token": "b"}, "a"},
+		{"未配置的自定义请求头", "", map[string]string{"X-Rainbond-Token": "abc"}, ""},
+		{"没有令牌", "X-Rainbond-Token", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAuthenticator(api.NewPool("http://127.0.0.1"), tt.header)
+			r := httptest.NewRequest(http.MethodPost, "/message", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := a.extractToken(r); got != tt.want {
+				t.Errorf("extractToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMessageHandler 验证会话请求必须携带令牌，且令牌与会话绑定的一致
+func TestMessageHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		token     string
+		wantCode  int
+		wantToken string
+	}{
+		{"绑定会话携带一致的令牌", "/message?sessionID=s1", "Bearer bound", http.StatusOK, "bound"},
+		{"绑定会话缺少令牌", "/message?sessionID=s1", "", http.StatusUnauthorized, ""},
+		{"绑定会话令牌不一致", "/message?sessionID=s1", "Bearer good", http.StatusForbidden, ""},
+		{"查询参数携带令牌", "/message?sessionID=s1&rainbond_token=bound", "", http.StatusUnauthorized, ""},
+		{"查询参数和请求头同时携带令牌", "/message?sessionID=s1&rainbond_token=bound", "Bearer bound", http.StatusUnauthorized, ""},
+		{"未绑定会话携带有效令牌", "/message?sessionID=s2", "Bearer good", http.StatusOK, "good"},
+		{"未绑定会话携带无效令牌", "/message?sessionID=s2", "Bearer bad", http.StatusUnauthorized, ""},
+		{"未绑定会话缺少令牌", "/message?sessionID=s2", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAuthenticator(t, "")
+			a.bind("s1", "bound")
+			var gotToken string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotToken, _ = api.TokenFromContext(r.Context())
+			})
+			send := func(context.Context, string, []byte) error { return nil }
+
+			r := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", tt.token)
+			}
+			w := httptest.NewRecorder()
+			a.MessageHandler(next, send).ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("状态码 = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if gotToken != tt.wantToken {
+				t.Errorf("传给MCP服务器的令牌 = %q, want %q", gotToken, tt.wantToken)
+			}
+		})
+	}
+}
+
+// TestStreamableHandlerRejectsQueryToken 验证 /mcp 端点拒绝查询参数中的令牌
+func TestStreamableHandlerRejectsQueryToken(t *testing.T) {
+	a := newTestAuthenticator(t, "")
+	called := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp?rainbond_token=good", nil)
+	w := httptest.NewRecorder()
+	a.StreamableHandler(next).ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || called {
+		t.Fatalf("状态码 = %d, called = %v, want 401且不调用MCP服务器", w.Code, called)
+	}
+	if w.Header().Get("WWW-Authenticate") == "" {
+		t.Error("401响应缺少WWW-Authenticate头")
+	}
+}
+
+// TestSessionBinding 验证绑定、解除绑定和空闲过期
+func TestSessionBinding(t *testing.T) {
+	a := newTestAuthenticator(t, "")
+
+	a.bind("s1", "t1")
+	token, done, ok := a.sessionToken("s1")
+	if !ok || token != "t1" {
+		t.Fatalf("sessionToken() = %q, %v, want t1, true", token, ok)
+	}
+	a.unbind("s1")
+	select {
+	case <-done:
+	default:
+		t.Fatal("解除绑定后会话的done应关闭")
+	}
+	if _, _, ok := a.sessionToken("s1"); ok {
+		t.Fatal("解除绑定后会话不应再有令牌")
+	}
+	if _, _, ok := a.sessionToken(""); ok {
+		t.Fatal("空会话ID不应有令牌")
+	}
+
+	a.idleTTL = 10 * time.Millisecond
+	a.bind("s2", "t2")
+	_, done, _ = a.sessionToken("s2")
+	time.Sleep(20 * time.Millisecond)
+	if _, _, ok := a.sessionToken("s2"); ok {
+		t.Fatal("空闲超时的会话不应再有令牌")
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("空闲超时的会话done应关闭")
+	}
+
+	// 绑定新会话时清理其他空闲超时的会话
+	a.bind("s3", "t3")
+	_, done, _ = a.sessionToken("s3")
+	time.Sleep(20 * time.Millisecond)
+	a.bind("s4", "t4")
+	a.mu.Lock()
+	_, kept := a.sessions["s3"]
+	a.mu.Unlock()
+	if kept {
+		t.Fatal("绑定新会话时应清理空闲超时的会话")
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("被清理的会话done应关闭")
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// sseSessionRecorder 从SSE的endpoint事件中解析出会话ID并完成绑定
+type sseSessionRecorder struct {
+	http.ResponseWriter
+	bind      func(sessionID string)
+	sessionID string
+}
+
+func (r *sseSessionRecorder) Write(p []byte) (int, error) {
+	if r.sessionID == "" {
+		if sessionID := parseEndpointSessionID(p); sessionID != "" {
+			r.sessionID = sessionID
+			r.bind(sessionID)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *sseSessionRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// headerSessionRecorder 从Streamable HTTP初始化响应的请求头中读取会话ID并完成绑定
+type headerSessionRecorder struct {
+	http.ResponseWriter
+	bind        func(sessionID string)
+	wroteHeader bool
+}
+
+func (r *headerSessionRecorder) WriteHeader(statusCode int) {
+	r.capture()
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *headerSessionRecorder) Write(p []byte) (int, error) {
+	r.capture()
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *headerSessionRecorder) Flush() {
+	r.capture()
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *headerSessionRecorder) capture() {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	if sessionID := r.Header().Get(streamableSessionHeader); sessionID != "" {
+		r.bind(sessionID)
+	}
+}
+
+// parseEndpointSessionID 解析形如 "event: endpoint\ndata: /message?sessionID=xxx" 的事件
+func parseEndpointSessionID(p []byte) string {
+	if !bytes.Contains(p, []byte("event: endpoint")) {
+		return ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		endpoint, err := url.Parse(strings.TrimPrefix(line, "data: "))
+		if err != nil {
+			return ""
+		}
+		return endpoint.Query().Get(sseSessionQueryKey)
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/tools"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// maxValues 单次补全最多返回的候选数量，MCP规范规定不超过100
+const maxValues = 100
+
+// Timeout 单次补全查询列表的超时时间，补全用于输入提示，不应长时间等待
+const Timeout = 10 * time.Second
+
+// argumentKinds 可补全的参数名和对象类型。资源模板变量使用简写，提示词参数与工具参数同名
+var argumentKinds = map[string]string{
+	"team":        tools.ResolveTeam,
+	"team_alias":  tools.ResolveTeam,
+	"region":      tools.ResolveRegion,
+	"region_name": tools.ResolveRegion,
+	"app_id":      tools.ResolveApp,
+	"service_id":  tools.ResolveComponent,
+}
+
+// Completer 应答 completion/complete 请求，为提示词和资源模板补全团队、集群、应用和组件参数。
+// go-mcp 没有实现该方法，由传输之前的拦截层调用，候选来自调用方令牌可见的列表
+type Completer struct {
+	clients *api.Pool
+}
+
+// New 创建参数补全器
+func New(clients *api.Pool) *Completer {
+	return &Completer{clients: clients}
+}
+
+// Call 一个待应答的补全请求
+type Call struct {
+	id     json.RawMessage
+	params json.RawMessage
+}
+
+// Parse 判断客户端消息是否为补全请求
+func Parse(msg []byte) (*Call, bool) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(msg, &request); err != nil || len(request.ID) == 0 {
+		return nil, false
+	}
+	if request.Method != string(protocol.CompletionComplete) {
+		return nil, false
+	}
+	return &Call{id: request.ID, params: request.Params}, true
+}
+
+// Intercept 应答补全请求，其他消息返回false，交给MCP服务器处理
+func (c *Completer) Intercept(ctx context.Context, msg []byte) ([]byte, bool) {
+	call, ok := Parse(msg)
+	if !ok {
+		return nil, false
+	}
+	return c.Reply(ctx, call), true
+}
+
+// Reply 计算补全结果并生成JSON-RPC响应
+func (c *Completer) Reply(ctx context.Context, call *Call) []byte {
+	var response *protocol.JSONRPCResponse
+	result, err := c.Complete(ctx, call.params)
+	if err != nil {
+		response = protocol.NewJSONRPCErrorResponse(call.id, protocol.InvalidParams, err.Error())
+	} else {
+		response = protocol.NewJSONRPCSuccessResponse(call.id, result)
+	}
+	// 响应只包含字符串和请求中的原始ID，序列化不会失败
+	msg, _ := json.Marshal(response)
+	return msg
+}
+
+// request completion/complete 的参数。context是较新版本规范中客户端携带的已填写参数
+type request struct {
+	Ref struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	} `json:"ref"`
+	Argument struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"argument"`
+	Context struct {
+		Arguments map[string]string `json:"arguments"`
+	} `json:"context"`
+}
+
+// Complete 返回与已输入内容匹配的候选ID。应用需要已填写团队，组件需要已填写团队和应用，
+// 缺少时返回空列表；查询失败同样返回空列表，不打断客户端的输入
+func (c *Completer) Complete(ctx context.Context, params json.RawMessage) (*protocol.CompleteResult, error) {
+	var req request
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+	empty := protocol.NewCompleteResult([]string{}, false, 0)
+	kind, ok := argumentKinds[req.Argument.Name]
+	if !ok {
+		return empty, nil
+	}
+
+	client, err := c.clients.FromContext(ctx)
+	if err != nil {
+		logger.Warn("补全参数 %s 失败: %v", req.Argument.Name, err)
+		return empty, nil
+	}
+	args := req.Context.Arguments
+	scope := func(names ...string) string {
+		for _, name := range names {
+			if value := strings.TrimSpace(args[name]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	candidates, err := tools.Candidates(ctx, client, kind,
+		scope("team", "team_alias"), scope("region", "region_name"), scope("app_id"))
+	if err != nil {
+		logger.Warn("补全参数 %s 失败: %v", req.Argument.Name, err)
+		return empty, nil
+	}
+
+	value := strings.TrimSpace(req.Argument.Value)
+	values := []string{}
+	seen := make(map[string]struct{})
+	for _, candidate := range candidates {
+		if _, ok := seen[candidate.ID]; ok || !candidate.Contains(value) {
+			continue
+		}
+		seen[candidate.ID] = struct{}{}
+		values = append(values, candidate.ID)
+	}
+	total := len(values)
+	if total > maxValues {
+		values = values[:maxValues]
+	}
+	return protocol.NewCompleteResult(values, total > maxValues, total), nil
+}
@@ -0,0 +1,57 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"rainmcp/pkg/api"
+)
+
+// TestIntercept 验证补全请求按已填写的团队给出应用ID，其他消息不被拦截
+func TestIntercept(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi/v1/mcp/teams":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"team_alias":"开发","team_name":"dev","region_list":[{"region_name":"r1"}]}]}}`))
+		case "/openapi/v1/mcp/teams/开发/regions/r1/apps":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"group_id":7,"group_name":"shop"},{"group_id":8,"group_name":"blog"}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer stub.Close()
+	completer := New(api.NewPool(stub.URL))
+	ctx := api.ContextWithToken(context.Background(), "token")
+
+	if _, ok := completer.Intercept(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)); ok {
+		t.Fatal("非补全请求不应被拦截")
+	}
+
+	msg := []byte(`{"jsonrpc":"2.0","id":"c1","method":"completion/complete","params":{
+		"ref":{"type":"ref/prompt","name":"rainbond_diagnose_component"},
+		"argument":{"name":"app_id","value":"SH"},
+		"context":{"arguments":{"team_alias":"dev"}}}}`)
+	reply, ok := completer.Intercept(ctx, msg)
+	if !ok {
+		t.Fatal("补全请求应被拦截")
+	}
+	var response struct {
+		ID     string `json:"id"`
+		Result struct {
+			Completion struct {
+				Values []string `json:"values"`
+				Total  int      `json:"total"`
+			} `json:"completion"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(reply, &response); err != nil {
+		t.Fatalf("响应不是有效的JSON: %v", err)
+	}
+	if response.ID != "c1" || !reflect.DeepEqual(response.Result.Completion.Values, []string{"7"}) {
+		t.Fatalf("补全结果不正确: %s", reply)
+	}
+}
@@ -15,6 +15,13 @@ type PageInfo struct {
 	Total    int `json:"total"`
 }
 
+// Pagination 分页列表工具输出的分页信息，字段拼写与page、page_size输入参数一致
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+	Total    int `json:"total"`
+}
+
 // 团队相关模型
 // ===============
 
@@ -27,6 +34,7 @@ type TeamRegionInfo struct {
 // Team 表示Rainbond平台中的团队
 type Team struct {
 	TeamAlias  string           `json:"team_alias" description:"团队中文名称"`
+	TeamName   string           `json:"team_name,omitempty" description:"团队英文名称"`
 	CreateTime string           `json:"create_time"`
 	OwnerName  string           `json:"owner_name"`
 	RegionList []TeamRegionInfo `json:"region_list"`
@@ -107,8 +115,8 @@ type App struct {
 
 // AppsRequest 表示获取应用列表的请求参数
 type AppsRequest struct {
-	TeamAlias  string `json:"team_alias" description:"团队别名"`
-	RegionName string `json:"region_name" description:"集群名称"`
+	TeamAlias  string `json:"team_alias" description:"团队别名" resolve:"team"`
+	RegionName string `json:"region_name" description:"集群名称" resolve:"region"`
 }
 
 // AppItem 表示应用列表中的单个应用项（新版本）
@@ -158,9 +166,9 @@ type LegacyAppsResponse struct {
 
 // CreateAppRequest 创建应用的请求参数
 type CreateAppRequest struct {
-	TeamAlias  string `json:"team_alias" description:"团队别名"`
-	RegionName string `json:"region_name" description:"集群名称"`
-	AppName    string `json:"app_name" description:"应用名称"` // 修改为app_name字段
+	TeamAlias  string `json:"team_alias" description:"团队别名" resolve:"team"`
+	RegionName string `json:"region_name" description:"集群名称" resolve:"region"`
+	AppName    string `json:"app_name" description:"应用名称" max:"64"` // 修改为app_name字段
 }
 
 // CreateAppResponse 创建应用的响应
@@ -179,6 +187,7 @@ type ComponentPort struct {
 	IsInnerService bool   `json:"is_inner_service" description:"是否开启对内服务"`
 	IsOuterService bool   `json:"is_outer_service" description:"是否开启对外服务"`
 	K8sServiceName string `json:"k8s_service_name" description:"Kubernetes服务名称"`
+	PortAlias      string `json:"port_alias" description:"端口别名"`
 }
 
 // PortInfo 端口信息（新版本API响应）
@@ -217,12 +226,12 @@ type PortListResponse struct {
 
 // AddPortRequest 表示添加组件端口的请求参数
 type AddPortRequest struct {
-	TeamAlias      string `json:"team_alias" description:"团队别名"`
-	AppID          string `json:"app_id" description:"应用ID"`
-	ServiceID      string `json:"service_id" description:"组件ID"`
-	Port           int    `json:"port" description:"端口号"`
-	Protocol       string `json:"protocol" description:"协议类型，可选值：tcp/udp/http"`
-	IsOuterService bool   `json:"is_outer_service" description:"是否开启对外服务"`
+	TeamAlias      string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID          string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID      string `json:"service_id" description:"组件ID" resolve:"component"`
+	Port           int    `json:"port" description:"端口号" min:"1" max:"65535"`
+	Protocol       string `json:"protocol" description:"协议类型，可选值：tcp/udp/http" enum:"tcp,udp,http"`
+	IsOuterService bool   `json:"is_outer_service" description:"是否开启对外服务" default:"false"`
 }
 
 // UpdatePortRequest 表示更新组件端口的请求参数
@@ -232,54 +241,88 @@ type AddPortRequest struct {
 // - open_inner: 打开对内服务
 // - close_inner: 关闭对内服务
 // - change_protocol: 更改端口协议，需要提供protocol参数
-// - change_port_alias: 更改端口别名，需要提供port_alias和k8s_service_name参数
+// - change_port_alias: 更改端口别名，需要提供port_alias参数，k8s_service_name可选
 type UpdatePortRequest struct {
-	TenantID   string `json:"tenant_id" description:"团队ID"`
-	RegionName string `json:"region_name" description:"集群名称"`
-	AppID      string `json:"app_id" description:"应用ID"`
-	ServiceID  string `json:"service_id" description:"组件ID"`
-	Port       int    `json:"port" description:"端口号"`
-	Action     string `json:"action" description:"操作类型，可选值：open_outer/close_outer/open_inner/close_inner/change_protocol"`
-	Protocol   string `json:"protocol,omitempty" description:"协议类型，当action为change_protocol时使用，可选值：tcp/udp/http"`
+	TeamAlias      string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID          string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID      string `json:"service_id" description:"组件ID" resolve:"component"`
+	Port           int    `json:"port" description:"端口号" min:"1" max:"65535"`
+	Action         string `json:"action" description:"操作类型，可选值：open_outer/close_outer/open_inner/close_inner/change_protocol/change_port_alias" enum:"open_outer,close_outer,open_inner,close_inner,change_protocol,change_port_alias"`
+	Protocol       string `json:"protocol,omitempty" description:"协议类型，当action为change_protocol时使用，可选值：tcp/udp/http" enum:"tcp,udp,http"`
+	PortAlias      string `json:"port_alias,omitempty" description:"端口别名，当action为change_port_alias时使用，同时作为连接信息环境变量的前缀" pattern:"^[A-Za-z][A-Za-z0-9_]*$"`
+	K8sServiceName string `json:"k8s_service_name,omitempty" description:"Kubernetes服务名称，当action为change_port_alias时可选" pattern:"^[a-z]([-a-z0-9]*[a-z0-9])?$"`
 }
 
 // ListPortsRequest 表示获取组件端口列表的请求参数
 type ListPortsRequest struct {
-	TeamAlias string `json:"team_alias" description:"团队别名"`
-	AppID     string `json:"app_id" description:"应用ID"`
-	ServiceID string `json:"service_id" description:"组件ID"`
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
 }
 
 // DeletePortRequest 表示删除组件端口的请求参数
 type DeletePortRequest struct {
-	TenantID   string `json:"tenant_id" description:"团队ID"`
-	RegionName string `json:"region_name" description:"集群名称"`
-	AppID      string `json:"app_id" description:"应用ID"`
-	ServiceID  string `json:"service_id" description:"组件ID"`
-	Port       int    `json:"port" description:"端口号"`
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	Port      int    `json:"port" description:"端口号" min:"1" max:"65535"`
 }
 
 // BuildComponentRequest 表示构建组件的请求参数
 type BuildComponentRequest struct {
-	TeamAlias    string `json:"team_alias" description:"团队名称"`
-	RegionName   string `json:"region_name" description:"集群名称"`
-	AppID        string `json:"app_id" description:"应用ID"`
-	ServiceID    string `json:"service_id" description:"组件ID"`
-	IsDeploy     bool   `json:"is_deploy" description:"是否部署"`
-	BuildVersion string `json:"build_version,omitempty" description:"构建版本"`
+	TeamAlias    string `json:"team_alias" description:"团队名称" resolve:"team"`
+	RegionName   string `json:"region_name,omitempty" description:"集群名称，按名称查找应用时用于缩小范围" resolve:"region"`
+	AppID        string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID    string `json:"service_id" description:"组件ID" resolve:"component"`
+	IsDeploy     bool   `json:"is_deploy" description:"构建完成后是否部署" default:"true"`
+	BuildVersion string `json:"build_version,omitempty" description:"构建版本，留空时构建最新代码"`
+	Wait         bool   `json:"wait,omitempty" description:"是否等待构建结束，等待期间发送进度通知" default:"false"`
+	WaitTimeout  int    `json:"wait_timeout,omitempty" description:"等待构建结束的最长时间(秒)" default:"600" min:"30" max:"1800"`
+}
+
+// ComponentEvent 组件的操作事件，构建、部署等异步操作通过事件跟踪进度
+type ComponentEvent struct {
+	EventID     string `json:"event_id" description:"事件ID"`
+	OptType     string `json:"opt_type" description:"操作类型"`
+	Status      string `json:"status" description:"执行结果：success/failure/timeout"`
+	FinalStatus string `json:"final_status" description:"事件状态：complete表示已结束"`
+	Message     string `json:"message" description:"事件信息"`
+	CreateTime  string `json:"create_time" description:"开始时间"`
+	EndTime     string `json:"end_time" description:"结束时间"`
+	UserName    string `json:"user_name,omitempty" description:"操作人"`
+	// ServiceID 和 ServiceCName 只在应用事件中返回，表示事件所属的组件
+	ServiceID    string `json:"service_id,omitempty" description:"组件ID"`
+	ServiceCName string `json:"service_cname,omitempty" description:"组件名称"`
+}
+
+// Finished 判断事件是否已经结束
+func (e ComponentEvent) Finished() bool {
+	return e.FinalStatus == "complete"
+}
+
+// ComponentEventResponse 构建组件、查询单个事件的响应
+type ComponentEventResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		Bean ComponentEvent `json:"bean"`
+	} `json:"data"`
 }
 
 // ComponentDetailRequest 获取组件详情的请求参数
 type ComponentDetailRequest struct {
-	TeamAlias string `json:"team_alias" description:"团队别名"`
-	AppID     string `json:"app_id" description:"应用ID"`
-	ServiceID string `json:"service_id" description:"组件ID"`
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	// RevealSecrets 为true时输出敏感环境变量的明文，资源读取时始终为false
+	RevealSecrets bool `json:"reveal_secrets,omitempty" description:"是否显示敏感环境变量的明文，默认打码显示" default:"false"`
 }
 
 // ListComponentsRequest 获取应用下组件列表的请求参数
 type ListComponentsRequest struct {
-	TeamAlias string `json:"team_alias" description:"团队别名"`
-	AppID     string `json:"app_id" description:"应用ID"`
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
 }
 
 // ComponentBaseInfo 组件基本信息
@@ -292,10 +335,12 @@ type ComponentBaseInfo struct {
 
 // ComponentInfo 组件信息（新版本API响应）
 type ComponentInfo struct {
-	ServiceID    string `json:"service_id" description:"组件ID"`
-	ServiceCName string `json:"service_cname" description:"组件中文名称"`
-	UpdateTime   string `json:"update_time" description:"更新时间"`
-	Status       string `json:"status" description:"组件状态"`
+	ServiceID        string `json:"service_id" description:"组件ID"`
+	ServiceCName     string `json:"service_cname" description:"组件中文名称"`
+	ServiceAlias     string `json:"service_alias,omitempty" description:"组件别名"`
+	K8sComponentName string `json:"k8s_component_name,omitempty" description:"组件英文名称"`
+	UpdateTime       string `json:"update_time" description:"更新时间"`
+	Status           string `json:"status" description:"组件状态"`
 }
 
 // ComponentListData 组件列表响应中的数据部分
@@ -328,13 +373,15 @@ type ComponentEnv struct {
 	Name      string `json:"name" description:"显示名称"`
 	Scope     string `json:"scope" description:"作用域"`
 	IsChange  bool   `json:"is_change" description:"是否可更改"`
+	IsSecret  bool   `json:"is_secret" description:"是否标记为敏感信息"`
 }
 
 // ComponentVolume 组件存储卷信息
 type ComponentVolume struct {
 	VolumeName     string `json:"volume_name" description:"存储卷名称"`
 	VolumePath     string `json:"volume_path" description:"挂载路径"`
-	VolumeCapacity int    `json:"volume_capacity" description:"存储容量(GB)"`
+	VolumeCapacity int    `json:"volume_capacity" description:"存储容量(GB)，0表示不限制"`
+	VolumeType     string `json:"volume_type" description:"存储类型"`
 }
 
 // ComponentDetailInfo 组件详情信息（新版本API响应）
@@ -345,6 +392,8 @@ type ComponentDetailInfo struct {
 	UpdateTime   string              `json:"update_time" description:"更新时间"`
 	MinMemory    int                 `json:"min_memory" description:"内存配额(MB)"`
 	MinCPU       int                 `json:"min_cpu" description:"CPU配额(毫核)"`
+	MinNode      int                 `json:"min_node" description:"实例数"`
+	Status       string              `json:"status" description:"组件状态"`
 	StatusCN     string              `json:"status_cn" description:"状态中文"`
 	Ports        []ComponentPortInfo `json:"ports" description:"端口列表"`
 	Envs         []ComponentEnv      `json:"envs" description:"环境变量列表"`
@@ -364,13 +413,352 @@ type NewComponentDetailResponse struct {
 
 // CreateCodeComponentRequest 基于源码创建组件的请求参数
 type CreateCodeComponentRequest struct {
-	TeamAlias    string `json:"team_alias" description:"团队名称"`
-	AppID        string `json:"app_id" description:"应用ID"`
-	ServiceCName string `json:"service_cname" description:"组件名称"`
-	RepoURL      string `json:"repo_url" description:"代码仓库地址"`
-	Branch       string `json:"branch" description:"分支名称"`
+	TeamAlias    string `json:"team_alias" description:"团队名称" resolve:"team"`
+	AppID        string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceCName string `json:"service_cname" description:"组件名称" max:"64"`
+	RepoURL      string `json:"repo_url" description:"代码仓库地址" pattern:"^(https?|git|ssh)://|^git@"`
+	Branch       string `json:"branch" description:"分支名称" default:"master"`
 	Username     string `json:"username,omitempty" description:"仓库用户名"`
 	Password     string `json:"password,omitempty" description:"仓库密码"`
 }
 
+// CreateImageComponentRequest 基于镜像创建组件的请求参数
+type CreateImageComponentRequest struct {
+	TeamAlias        string               `json:"team_alias" description:"团队名称" resolve:"team"`
+	AppID            string               `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceCName     string               `json:"service_cname" description:"组件名称" max:"64"`
+	K8sComponentName string               `json:"k8s_component_name,omitempty" description:"组件英文名称，留空时自动生成" pattern:"^[a-z]([-a-z0-9]*[a-z0-9])?$" max:"32"`
+	Image            string               `json:"image" description:"镜像地址，例如 nginx:1.25 或 registry.example.com/team/app:v1" pattern:"^[^\\s]+$"`
+	Username         string               `json:"username,omitempty" description:"镜像仓库用户名，私有镜像需要"`
+	Password         string               `json:"password,omitempty" description:"镜像仓库密码，私有镜像需要"`
+	Cmd              string               `json:"cmd,omitempty" description:"启动命令，留空时使用镜像的默认命令"`
+	MinMemory        int                  `json:"min_memory,omitempty" description:"内存配额(MB)" default:"512" min:"64" max:"65536"`
+	MinCPU           int                  `json:"min_cpu,omitempty" description:"CPU配额(毫核)，0表示不限制" default:"0" min:"0" max:"64000"`
+	Envs             []ImageComponentEnv  `json:"envs,omitempty" description:"环境变量列表"`
+	Ports            []ImageComponentPort `json:"ports,omitempty" description:"端口列表"`
+	IsDeploy         bool                 `json:"is_deploy" description:"创建后是否立即部署" default:"true"`
+}
+
+// ImageComponentEnv 创建镜像组件时设置的环境变量
+type ImageComponentEnv struct {
+	Name  string `json:"name" description:"变量名" pattern:"^[A-Za-z_][A-Za-z0-9_.-]*$"`
+	Value string `json:"value" description:"变量值"`
+}
+
+// ImageComponentPort 创建镜像组件时添加的端口
+type ImageComponentPort struct {
+	Port           int    `json:"port" description:"端口号" min:"1" max:"65535"`
+	Protocol       string `json:"protocol,omitempty" description:"协议类型，可选值：tcp/udp/http" enum:"tcp,udp,http" default:"http"`
+	IsOuterService bool   `json:"is_outer_service,omitempty" description:"是否开启对外服务" default:"false"`
+}
+
+// CreateComponentResponse 创建组件的响应
+type CreateComponentResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		Bean ComponentInfo `json:"bean"`
+	} `json:"data"`
+}
+
+// ComponentActionRequest 组件生命周期操作的请求参数
+type ComponentActionRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	Action    string `json:"action" description:"操作类型：start启动/stop停止/restart重启/upgrade滚动更新" enum:"start,stop,restart,upgrade"`
+}
+
+// DeleteComponentRequest 删除组件的请求参数
+type DeleteComponentRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+}
+
+// AppComponentsActionRequest 批量操作应用下组件的请求参数
+type AppComponentsActionRequest struct {
+	TeamAlias  string   `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID      string   `json:"app_id" description:"应用ID" resolve:"app"`
+	Action     string   `json:"action" description:"操作类型：start启动/stop停止/restart重启/upgrade滚动更新" enum:"start,stop,restart,upgrade"`
+	ServiceIDs []string `json:"service_ids,omitempty" description:"只操作这些组件，可以填写组件ID或名称，留空时操作应用下的全部组件"`
+}
+
+// DeleteAppComponentsRequest 批量删除应用下组件的请求参数
+type DeleteAppComponentsRequest struct {
+	TeamAlias  string   `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID      string   `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceIDs []string `json:"service_ids,omitempty" description:"要删除的组件，可以填写组件ID或名称，与all必须二选一"`
+	All        bool     `json:"all,omitempty" description:"删除应用下的全部组件，必须显式设为true，不能与service_ids同时指定" default:"false"`
+}
+
+// ScaleReplicasRequest 水平伸缩组件的请求参数
+type ScaleReplicasRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	Replicas  int    `json:"replicas" description:"调整后的实例数" min:"1" max:"100"`
+}
+
+// ScaleResourcesRequest 垂直伸缩组件的请求参数，不填的配额保持不变
+type ScaleResourcesRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	MinMemory *int   `json:"min_memory,omitempty" description:"调整后的内存配额(MB)，不填时保持不变" min:"64" max:"65536"`
+	MinCPU    *int   `json:"min_cpu,omitempty" description:"调整后的CPU配额(毫核)，0表示不限制，不填时保持不变" min:"0" max:"64000"`
+}
+
+// AddEnvRequest 新增组件环境变量的请求参数
+type AddEnvRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	AttrName  string `json:"attr_name" description:"变量名" pattern:"^[A-Za-z_][A-Za-z0-9_.-]*$" max:"1024"`
+	AttrValue string `json:"attr_value" description:"变量值"`
+	Name      string `json:"name,omitempty" description:"显示名称"`
+	Scope     string `json:"scope,omitempty" description:"作用域：inner为组件自身的环境变量，outer为提供给依赖它的组件的连接信息" enum:"inner,outer" default:"inner"`
+	IsSecret  bool   `json:"is_secret,omitempty" description:"是否标记为敏感信息，敏感变量在输出中打码显示" default:"false"`
+}
+
+// UpdateEnvRequest 更新组件环境变量的请求参数
+type UpdateEnvRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	AttrName  string `json:"attr_name" description:"变量名"`
+	AttrValue string `json:"attr_value" description:"新的变量值"`
+	Name      string `json:"name,omitempty" description:"新的显示名称，留空时保持不变"`
+	Scope     string `json:"scope,omitempty" description:"作用域，同名变量同时存在于inner和outer时必须指定" enum:"inner,outer"`
+}
+
+// DeleteEnvRequest 删除组件环境变量的请求参数
+type DeleteEnvRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	AttrName  string `json:"attr_name" description:"变量名"`
+	Scope     string `json:"scope,omitempty" description:"作用域，同名变量同时存在于inner和outer时必须指定" enum:"inner,outer"`
+}
+
+// SetEnvsRequest 批量设置组件环境变量的请求参数，已存在的变量更新取值，不存在的新增，未列出的保持不变
+type SetEnvsRequest struct {
+	TeamAlias string       `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string       `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string       `json:"service_id" description:"组件ID" resolve:"component"`
+	Scope     string       `json:"scope,omitempty" description:"这批变量的作用域：inner为组件自身的环境变量，outer为提供给依赖它的组件的连接信息" enum:"inner,outer" default:"inner"`
+	Envs      []EnvSetting `json:"envs" description:"要设置的环境变量列表"`
+}
+
+// EnvSetting 批量设置时的单个环境变量
+type EnvSetting struct {
+	AttrName  string `json:"attr_name" description:"变量名" pattern:"^[A-Za-z_][A-Za-z0-9_.-]*$" max:"1024"`
+	AttrValue string `json:"attr_value" description:"变量值"`
+	Name      string `json:"name,omitempty" description:"显示名称"`
+	IsSecret  bool   `json:"is_secret,omitempty" description:"是否标记为敏感信息，只对新增的变量生效" default:"false"`
+}
+
+// AddVolumeRequest 为组件添加存储卷的请求参数
+type AddVolumeRequest struct {
+	TeamAlias      string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID          string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID      string `json:"service_id" description:"组件ID" resolve:"component"`
+	VolumeName     string `json:"volume_name" description:"存储卷名称" pattern:"^[a-z0-9]([-a-z0-9]*[a-z0-9])?$" max:"60"`
+	VolumePath     string `json:"volume_path" description:"挂载路径，必须是绝对路径" pattern:"^/[^\\s]+$"`
+	VolumeType     string `json:"volume_type,omitempty" description:"存储类型：share-file为共享存储，可以被其他组件共享；local为本地存储；config-file为配置文件" enum:"share-file,local,config-file" default:"share-file"`
+	VolumeCapacity int    `json:"volume_capacity,omitempty" description:"存储容量(GB)，0表示不限制，配置文件不需要" default:"0" min:"0" max:"10240"`
+	FileContent    string `json:"file_content,omitempty" description:"配置文件内容，volume_type为config-file时必填"`
+}
+
+// ResizeVolumeRequest 调整存储卷容量的请求参数
+type ResizeVolumeRequest struct {
+	TeamAlias      string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID          string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID      string `json:"service_id" description:"组件ID" resolve:"component"`
+	VolumeName     string `json:"volume_name" description:"存储卷名称"`
+	VolumeCapacity int    `json:"volume_capacity" description:"调整后的存储容量(GB)，只能扩容" min:"1" max:"10240"`
+}
+
+// DeleteVolumeRequest 删除组件存储卷的请求参数
+type DeleteVolumeRequest struct {
+	TeamAlias  string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID      string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID  string `json:"service_id" description:"组件ID" resolve:"component"`
+	VolumeName string `json:"volume_name" description:"存储卷名称"`
+	Force      bool   `json:"force,omitempty" description:"组件未停止时是否仍然删除，默认拒绝" default:"false"`
+}
+
+// ShareVolumeRequest 挂载同一应用下其他组件共享存储的请求参数
+type ShareVolumeRequest struct {
+	TeamAlias       string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID           string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID       string `json:"service_id" description:"挂载存储的组件ID" resolve:"component"`
+	SourceServiceID string `json:"source_service_id" description:"提供存储的组件ID，也可以填写组件名称或组件英文名称，需要与挂载组件在同一应用下"`
+	VolumeName      string `json:"volume_name" description:"提供存储的组件上的存储卷名称"`
+	VolumePath      string `json:"volume_path" description:"在挂载组件中的挂载路径，必须是绝对路径" pattern:"^/[^\\s]+$"`
+}
+
+// ComponentLogsRequest 获取组件运行日志的请求参数
+type ComponentLogsRequest struct {
+	TeamAlias     string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID         string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID     string `json:"service_id" description:"组件ID" resolve:"component"`
+	Lines         int    `json:"lines,omitempty" description:"获取最近的日志行数" default:"100" min:"1" max:"1000"`
+	Since         string `json:"since,omitempty" description:"只获取该时间之后的日志，可以是时长(如 10m、2h)或RFC3339时间(如 2024-01-02T15:04:05+08:00)"`
+	PodName       string `json:"pod_name,omitempty" description:"只获取该实例的日志，留空时获取全部实例"`
+	Grep          string `json:"grep,omitempty" description:"只保留包含该文本的行，不区分大小写"`
+	Follow        bool   `json:"follow,omitempty" description:"获取最近的日志后是否继续跟踪新日志，新日志通过进度通知推送" default:"false"`
+	FollowSeconds int    `json:"follow_seconds,omitempty" description:"跟踪新日志的最长时间(秒)" default:"60" min:"5" max:"600"`
+	MaxLines      int    `json:"max_lines,omitempty" description:"跟踪时最多推送的新日志行数，达到后停止跟踪" default:"500" min:"1" max:"5000"`
+}
+
+// ComponentLogsResponse 组件运行日志的响应，每个元素为一行日志
+type ComponentLogsResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		List []string `json:"list"`
+	} `json:"data"`
+}
+
+// ComponentEventsRequest 分页获取组件操作事件的请求参数
+type ComponentEventsRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	Page      int    `json:"page,omitempty" description:"页码，从1开始" default:"1" min:"1"`
+	PageSize  int    `json:"page_size,omitempty" description:"每页数量" default:"20" min:"1" max:"100"`
+}
+
+// AppEventsRequest 分页获取应用下所有组件操作事件的请求参数
+type AppEventsRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	Page      int    `json:"page,omitempty" description:"页码，从1开始" default:"1" min:"1"`
+	PageSize  int    `json:"page_size,omitempty" description:"每页数量" default:"20" min:"1" max:"100"`
+}
+
+// ComponentEventListResponse 操作事件列表的响应，按开始时间倒序
+type ComponentEventListResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		List  []ComponentEvent `json:"list"`
+		Total int              `json:"total"`
+	} `json:"data"`
+}
+
+// EventLogRequest 获取操作事件日志的请求参数
+type EventLogRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	EventID   string `json:"event_id" description:"事件ID，可以通过 rainbond_list_component_events 获取"`
+}
+
+// EventLogLine 操作事件日志中的一行，构建事件为构建日志，部署等事件为执行过程
+type EventLogLine struct {
+	Message string `json:"message" description:"日志内容"`
+	Time    string `json:"time" description:"时间"`
+	Step    string `json:"step" description:"执行步骤"`
+	Status  string `json:"status" description:"步骤状态"`
+}
+
+// EventLogResponse 操作事件日志的响应
+type EventLogResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		List []EventLogLine `json:"list"`
+	} `json:"data"`
+}
+
+// BuildVersionsRequest 分页获取组件构建版本的请求参数
+type BuildVersionsRequest struct {
+	TeamAlias string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID     string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID string `json:"service_id" description:"组件ID" resolve:"component"`
+	Page      int    `json:"page,omitempty" description:"页码，从1开始" default:"1" min:"1"`
+	PageSize  int    `json:"page_size,omitempty" description:"每页数量" default:"20" min:"1" max:"100"`
+}
+
+// BuildVersion 组件的一个构建版本
+type BuildVersion struct {
+	BuildVersion string `json:"build_version" description:"构建版本"`
+	CommitHash   string `json:"commit_hash" description:"代码提交"`
+	CommitMsg    string `json:"commit_msg" description:"提交信息"`
+	ImageURL     string `json:"image_url" description:"镜像地址"`
+	CreateTime   string `json:"create_time" description:"构建时间"`
+	Status       string `json:"status" description:"构建结果：success/failure"`
+	EventID      string `json:"event_id" description:"构建事件ID"`
+}
+
+// BuildVersionsResponse 构建版本列表的响应，按构建时间倒序
+type BuildVersionsResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		Bean struct {
+			CurrentVersion string `json:"current_version"`
+		} `json:"bean"`
+		List  []BuildVersion `json:"list"`
+		Total int            `json:"total"`
+	} `json:"data"`
+}
+
+// RollbackRequest 回滚组件到指定构建版本的请求参数
+type RollbackRequest struct {
+	TeamAlias    string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID        string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID    string `json:"service_id" description:"组件ID" resolve:"component"`
+	BuildVersion string `json:"build_version" description:"要回滚到的构建版本，可以通过 rainbond_list_build_versions 获取"`
+}
+
+// DependenciesRequest 获取组件依赖关系的请求参数
+type DependenciesRequest struct {
+	TeamAlias     string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID         string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID     string `json:"service_id" description:"组件ID" resolve:"component"`
+	RevealSecrets bool   `json:"reveal_secrets,omitempty" description:"是否显示敏感连接信息的明文，默认打码显示" default:"false"`
+}
+
+// DependencyRequest 添加或删除组件依赖的请求参数
+type DependencyRequest struct {
+	TeamAlias    string `json:"team_alias" description:"团队别名" resolve:"team"`
+	AppID        string `json:"app_id" description:"应用ID" resolve:"app"`
+	ServiceID    string `json:"service_id" description:"发起依赖的组件ID，例如Web应用" resolve:"component"`
+	DepServiceID string `json:"dep_service_id" description:"被依赖的组件ID，例如数据库，也可以填写组件名称或组件英文名称"`
+	DepAppID     string `json:"dep_app_id,omitempty" description:"被依赖组件所在的应用ID或应用名称，留空时与发起依赖的组件在同一应用"`
+}
+
+// ComponentDependency 依赖关系中的另一方组件
+type ComponentDependency struct {
+	ServiceID        string `json:"service_id" description:"组件ID"`
+	ServiceCName     string `json:"service_cname" description:"组件名称"`
+	K8sComponentName string `json:"k8s_component_name" description:"组件英文名称"`
+	GroupID          int    `json:"group_id" description:"所在应用ID"`
+	GroupName        string `json:"group_name" description:"所在应用名称"`
+	Status           string `json:"status" description:"组件状态"`
+}
+
+// DependenciesResponse 组件依赖关系的响应
+type DependenciesResponse struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	MsgShow string `json:"msg_show"`
+	Data    struct {
+		Bean struct {
+			// Dependencies 该组件依赖的组件
+			Dependencies []ComponentDependency `json:"dependencies"`
+			// Dependents 依赖该组件的组件
+			Dependents []ComponentDependency `json:"dependents"`
+		} `json:"bean"`
+	} `json:"data"`
+}
+
 type RainTokenKey struct{}
@@ -0,0 +1,138 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"rainmcp/pkg/logger"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// definition 一个预置提示词：参数说明和生成提示内容的模板
+type definition struct {
+	prompt protocol.Prompt
+	// defaults 可选参数未传入时使用的默认值
+	defaults map[string]string
+	text     *template.Template
+}
+
+// definitions 预置的常用流程提示词，模板中按顺序写明需要调用的工具和参数
+var definitions = []definition{
+	{
+		prompt: protocol.Prompt{
+			Name:        "rainbond_deploy_git_repo",
+			Description: "把Git仓库部署到指定团队：准备应用、基于源码创建组件、等待运行并按需开放端口",
+			Arguments: []protocol.PromptArgument{
+				{Name: "team_alias", Description: "团队别名或团队名称", Required: true},
+				{Name: "region_name", Description: "集群名称", Required: true},
+				{Name: "repo_url", Description: "代码仓库地址", Required: true},
+				{Name: "app_name", Description: "应用名称，不存在时自动创建，默认使用仓库名"},
+				{Name: "branch", Description: "分支名称，默认为master"},
+				{Name: "port", Description: "需要对外开放的端口，留空表示不开放"},
+			},
+		},
+		defaults: map[string]string{"branch": "master"},
+		text: parse("rainbond_deploy_git_repo", `请把代码仓库 {{.repo_url}} 的 {{.branch}} 分支部署到Rainbond团队 {{.team_alias}} 的集群 {{.region_name}} 中，按以下步骤依次调用工具，每一步确认成功后再继续：
+
+1. 调用 rainbond_teams，确认团队 {{.team_alias}} 存在并且开通了集群 {{.region_name}}。
+2. 调用 rainbond_apps（team_alias={{.team_alias}}, region_name={{.region_name}}），查找{{if .app_name}}名为 {{.app_name}} 的应用{{else}}与仓库同名的应用{{end}}，已存在时直接使用它的应用ID。
+3. 应用不存在时调用 rainbond_create_app（team_alias={{.team_alias}}, region_name={{.region_name}}, app_name={{if .app_name}}{{.app_name}}{{else}}仓库名{{end}}）创建应用，记下返回的应用ID。
+4. 调用 rainbond_create_code_component（team_alias={{.team_alias}}, app_id=上一步的应用ID, service_cname=仓库名, repo_url={{.repo_url}}, branch={{.branch}}）创建组件并自动构建部署。私有仓库需要向我询问 username 和 password。
+5. 调用 rainbond_list_components（team_alias={{.team_alias}}, app_id=第2、3步得到的应用ID）找到新组件的ID，再调用 rainbond_get_component_detail（team_alias={{.team_alias}}, app_id=第2、3步得到的应用ID, service_id=新组件的ID）查看运行状态。构建需要几分钟，状态不是运行中时间隔一段时间再查询，不要连续重复调用。
+{{- if .port}}
+6. 组件运行后调用 rainbond_add_component_port（team_alias={{.team_alias}}, app_id=第2、3步得到的应用ID, service_id=第5步的组件ID, port={{.port}}, protocol=http, is_outer_service=true）开放端口，再调用 rainbond_get_component_detail（参数同第5步）获取访问地址。
+{{- end}}
+
+完成后告诉我应用ID、组件ID、组件状态{{if .port}}和访问地址{{end}}。任何一步失败时停止后续步骤，说明失败原因和错误信息中的建议。`),
+	},
+	{
+		prompt: protocol.Prompt{
+			Name:        "rainbond_diagnose_component",
+			Description: "诊断组件为什么没有正常运行：检查状态、日志、最近的构建和部署事件、资源配额、端口、环境变量和存储卷",
+			Arguments: []protocol.PromptArgument{
+				{Name: "team_alias", Description: "团队别名或团队名称", Required: true},
+				{Name: "app_id", Description: "应用ID或应用名称", Required: true},
+				{Name: "service_id", Description: "组件ID、组件名称或组件英文名称", Required: true},
+			},
+		},
+		text: parse("rainbond_diagnose_component", `组件 {{.service_id}}（团队 {{.team_alias}}，应用 {{.app_id}}）没有正常运行，请帮我诊断原因。按以下步骤调用工具，只读取信息，不要修改组件：
+
+1. 调用 rainbond_get_component_detail（team_alias={{.team_alias}}, app_id={{.app_id}}, service_id={{.service_id}}），查看运行状态、实例数、内存和CPU配额。
+2. 调用 rainbond_get_component_logs（参数同第1步, lines=200），查找启动报错、异常堆栈、连接失败和内存不足被终止等信息。组件没有在运行时日志可能为空，以其他步骤的结果为准。
+3. 调用 rainbond_list_component_events（参数同第1步），查看最近的构建、部署、启动等操作是否失败。找到最近一次失败的事件后，调用 rainbond_get_component_event_log（参数同第1步, event_id=该事件的ID）读取构建或部署日志，定位失败的具体步骤。
+4. 调用 rainbond_list_component_ports（参数同第1步），确认端口和协议与应用实际监听的端口一致，日志中的监听端口可以作为依据。
+5. 检查组件详情中的环境变量是否缺少数据库地址、密钥等必需配置，存储卷的挂载路径是否与应用的数据目录一致。
+6. 调用 rainbond_list_component_dependencies（参数同第1步），查看组件依赖的其他组件是否正常运行，以及依赖注入的连接信息是否是应用读取的变量名。
+
+最后按可能性从高到低列出原因，每条给出依据和建议的修复操作，涉及修改的操作先征求我的同意。`),
+	},
+	{
+		prompt: protocol.Prompt{
+			Name:        "rainbond_expose_component",
+			Description: "把组件的端口开放到公网并返回访问地址",
+			Arguments: []protocol.PromptArgument{
+				{Name: "team_alias", Description: "团队别名或团队名称", Required: true},
+				{Name: "app_id", Description: "应用ID或应用名称", Required: true},
+				{Name: "service_id", Description: "组件ID、组件名称或组件英文名称", Required: true},
+				{Name: "port", Description: "需要开放的端口，留空时使用组件已有的端口"},
+				{Name: "protocol", Description: "端口协议，可选值：http/tcp/udp，默认为http"},
+			},
+		},
+		defaults: map[string]string{"protocol": "http"},
+		text: parse("rainbond_expose_component", `请把组件 {{.service_id}}（团队 {{.team_alias}}，应用 {{.app_id}}）{{if .port}}的 {{.port}} 端口{{end}}开放到公网，按以下步骤调用工具：
+
+1. 调用 rainbond_list_component_ports（team_alias={{.team_alias}}, app_id={{.app_id}}, service_id={{.service_id}}）查看已有端口。
+2. {{if .port}}端口 {{.port}} 不存在时{{else}}组件没有端口时先向我确认端口号，然后{{end}}调用 rainbond_add_component_port（team_alias={{.team_alias}}, app_id={{.app_id}}, service_id={{.service_id}}, port={{if .port}}{{.port}}{{else}}确认的端口号{{end}}, protocol={{.protocol}}, is_outer_service=true）添加端口并开放对外服务；端口已存在但没有开放对外服务时，调用 rainbond_update_component_port（team_alias={{.team_alias}}, app_id={{.app_id}}, service_id={{.service_id}}, port={{if .port}}{{.port}}{{else}}确认的端口号{{end}}, action=open_outer）开启。
+3. 上一步的结果中包含访问地址；没有时调用 rainbond_get_component_detail（参数同第1步）查看端口的访问地址。
+
+完成后告诉我访问地址。{{if eq .protocol "http"}}HTTP端口会分配域名访问地址；{{end}}组件未运行时访问地址无法打开，需要提醒我先启动组件。`),
+	},
+}
+
+// parse 解析提示词模板，模板写在代码中，出错即为编程错误
+func parse(name, text string) *template.Template {
+	return template.Must(template.New(name).Option("missingkey=zero").Parse(text))
+}
+
+// Register 把预置提示词注册到MCP服务器
+func Register(mcpServer *server.Server) {
+	for i := range definitions {
+		def := definitions[i]
+		mcpServer.RegisterPrompt(&def.prompt, def.handle)
+	}
+	logger.Info("[提示词] 已注册 %d 个提示词", len(definitions))
+}
+
+// handle 校验必填参数并渲染提示内容
+func (def definition) handle(_ context.Context, request *protocol.GetPromptRequest) (*protocol.GetPromptResult, error) {
+	args := make(map[string]string, len(def.prompt.Arguments))
+	var missing []string
+	for _, arg := range def.prompt.Arguments {
+		value := strings.TrimSpace(request.Arguments[arg.Name])
+		if value == "" {
+			value = def.defaults[arg.Name]
+		}
+		if value == "" && arg.Required {
+			missing = append(missing, arg.Name)
+		}
+		args[arg.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("提示词 %s 缺少必填参数: %s", def.prompt.Name, strings.Join(missing, ", "))
+	}
+
+	var text strings.Builder
+	if err := def.text.Execute(&text, args); err != nil {
+		return nil, fmt.Errorf("生成提示词 %s 失败: %v", def.prompt.Name, err)
+	}
+	return protocol.NewGetPromptResult([]protocol.PromptMessage{
+		{
+			Role:    protocol.RoleUser,
+			Content: &protocol.TextContent{Type: "text", Text: text.String()},
+		},
+	}, def.prompt.Description), nil
+}
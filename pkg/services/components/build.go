@@ -0,0 +1,126 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+const (
+	// buildPollInterval 等待构建时查询事件状态的间隔
+	buildPollInterval = 5 * time.Second
+	// returnMargin 等待或跟踪时在工具调用截止之前留出的返回时间，保证超时前仍能返回已有的结果
+	returnMargin = 5 * time.Second
+)
+
+// buildResult 构建结果，waited为false时只包含触发构建返回的事件
+type buildResult struct {
+	event   models.ComponentEvent
+	waited  bool
+	elapsed time.Duration
+}
+
+// buildComponentTool 构建组件，可选等待构建结束
+var buildComponentTool = tools.Spec[models.BuildComponentRequest, buildResult]{
+	Name:        "rainbond_build_component",
+	Description: "在Rainbond平台中构建组件并返回构建事件ID。构建通常需要几分钟，wait为true时等待构建结束并报告进度",
+	Action:      "构建组件",
+	Call:        buildComponent,
+	Render:      renderBuildResult,
+}
+
+// buildComponent 触发构建，需要等待时轮询构建事件直到结束、超时或调用被取消
+func buildComponent(ctx context.Context, client *api.Client, req *models.BuildComponentRequest) (*buildResult, error) {
+	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/build", req.TeamAlias, req.AppID, req.ServiceID)
+	logger.Info("构建组件: POST %s", path)
+	body := map[string]interface{}{"is_deploy": req.IsDeploy}
+	if req.BuildVersion != "" {
+		body["build_version"] = req.BuildVersion
+	}
+	resp, err := tools.Do[models.ComponentEventResponse](ctx, client, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	result := &buildResult{event: resp.Data.Bean}
+	if !req.Wait || result.event.EventID == "" {
+		return result, nil
+	}
+
+	start := time.Now()
+	deadline := start.Add(time.Duration(req.WaitTimeout) * time.Second)
+	if d, ok := ctx.Deadline(); ok && d.Add(-returnMargin).Before(deadline) {
+		deadline = d.Add(-returnMargin)
+	}
+	total := deadline.Sub(start).Seconds()
+	eventPath := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/events/%s",
+		req.TeamAlias, req.AppID, req.ServiceID, result.event.EventID)
+
+	result.waited = true
+	ticker := time.NewTicker(buildPollInterval)
+	defer ticker.Stop()
+	for !result.event.Finished() {
+		elapsed := time.Since(start)
+		tools.Progress(ctx, elapsed.Seconds(), total, fmt.Sprintf("组件构建中，已等待%s", elapsed.Round(time.Second)))
+		if time.Now().Add(buildPollInterval).After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		event, err := tools.Do[models.ComponentEventResponse](ctx, client, http.MethodGet, eventPath, nil)
+		if err != nil {
+			// 查询事件偶尔失败不影响构建本身，下一轮继续查询
+			logger.Warn("查询构建事件 %s 失败: %v", result.event.EventID, err)
+			continue
+		}
+		result.event = event.Data.Bean
+	}
+	result.elapsed = time.Since(start)
+	if result.event.Finished() {
+		tools.Progress(ctx, total, total, "组件构建已结束")
+	}
+	return result, nil
+}
+
+// renderBuildResult 输出构建事件ID和等待结果
+func renderBuildResult(req *models.BuildComponentRequest, result *buildResult) (interface{}, error) {
+	event := result.event
+	output := map[string]interface{}{
+		"构建事件ID": event.EventID,
+		"构建后部署":  req.IsDeploy,
+	}
+	if !result.waited {
+		output["说明"] = "构建已开始，通常需要几分钟，可以稍后查看组件详情中的运行状态"
+		return output, nil
+	}
+
+	output["等待时间"] = result.elapsed.Round(time.Second).String()
+	if !event.Finished() {
+		output["构建状态"] = "构建中"
+		output["说明"] = "等待时间已到，构建仍在进行，可以稍后使用构建事件ID查看结果"
+		return output, nil
+	}
+	output["构建状态"] = buildStatusNames[event.Status]
+	if output["构建状态"] == "" {
+		output["构建状态"] = event.Status
+	}
+	if event.Message != "" {
+		output["事件信息"] = event.Message
+	}
+	return output, nil
+}
+
+// buildStatusNames 构建事件执行结果的中文说明
+var buildStatusNames = map[string]string{
+	"success": "构建成功",
+	"failure": "构建失败",
+	"timeout": "构建超时",
+}
@@ -0,0 +1,289 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/utils"
+)
+
+// dependencyInfo 依赖的组件及其注入的连接信息
+type dependencyInfo struct {
+	component models.ComponentDependency
+	envs      []models.ComponentEnv
+	// envErr 查询连接信息失败，不影响依赖关系本身
+	envErr error
+}
+
+// dependenciesResult 组件的依赖关系
+type dependenciesResult struct {
+	dependencies []dependencyInfo
+	dependents   []models.ComponentDependency
+}
+
+// dependencyResult 添加或删除依赖的结果
+type dependencyResult struct {
+	dependency dependencyInfo
+	added      bool
+}
+
+// listDependenciesTool 获取组件依赖的组件和依赖它的组件
+var listDependenciesTool = tools.Spec[models.DependenciesRequest, dependenciesResult]{
+	Name:        "rainbond_list_component_dependencies",
+	Description: "获取Rainbond平台中组件依赖的组件和依赖它的组件，以及每个依赖注入到该组件的连接信息环境变量",
+	Action:      "获取组件依赖关系",
+	Call: func(ctx context.Context, client *api.Client, req *models.DependenciesRequest) (*dependenciesResult, error) {
+		resp, err := fetchDependencies(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		result := &dependenciesResult{dependents: resp.Data.Bean.Dependents}
+		for _, dep := range resp.Data.Bean.Dependencies {
+			result.dependencies = append(result.dependencies, withConnectionEnvs(ctx, client, req.TeamAlias, req.AppID, dep))
+		}
+		return result, nil
+	},
+	Render: renderDependencies,
+}
+
+// addDependencyTool 添加组件依赖，被依赖组件可以在其他应用中
+var addDependencyTool = tools.Spec[models.DependencyRequest, dependencyResult]{
+	Name:        "rainbond_add_component_dependency",
+	Description: "在Rainbond平台中让组件依赖另一个组件（可以在其他应用中），被依赖组件的连接信息会作为环境变量注入，返回注入的变量。需要重启组件才能生效",
+	Action:      "添加组件依赖",
+	Call:        addDependency,
+	Render:      renderDependencyResult,
+}
+
+// deleteDependencyTool 删除组件依赖
+var deleteDependencyTool = tools.Spec[models.DependencyRequest, dependencyResult]{
+	Name:        "rainbond_delete_component_dependency",
+	Description: "在Rainbond平台中删除组件对另一个组件的依赖，返回不再注入的连接信息变量。需要重启组件才能生效",
+	Action:      "删除组件依赖",
+	Call:        deleteDependency,
+	Render:      renderDependencyResult,
+}
+
+// addDependency 解析被依赖组件并检查后添加依赖
+func addDependency(ctx context.Context, client *api.Client, req *models.DependencyRequest) (*dependencyResult, error) {
+	depAppID := req.AppID
+	if input := strings.TrimSpace(req.DepAppID); input != "" {
+		id, err := tools.AppID(ctx, client, req.TeamAlias, input)
+		if err != nil {
+			return nil, err
+		}
+		depAppID = id
+	}
+	depID, err := tools.ComponentID(ctx, client, req.TeamAlias, depAppID, strings.TrimSpace(req.DepServiceID))
+	if err != nil {
+		return nil, err
+	}
+	if depID == req.ServiceID {
+		return nil, errors.New("组件不能依赖自身")
+	}
+
+	existing, err := fetchDependencies(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range existing.Data.Bean.Dependencies {
+		if dep.ServiceID == depID {
+			return nil, fmt.Errorf("组件已经依赖 %s", dependencyName(dep))
+		}
+	}
+
+	path := dependenciesPath(req.TeamAlias, req.AppID, req.ServiceID, "")
+	logger.Info("添加组件依赖: POST %s %s", path, depID)
+	if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPost, path, map[string]interface{}{
+		"dep_service_id": depID,
+		"dep_app_id":     depAppID,
+	}); err != nil {
+		return nil, err
+	}
+
+	dep := models.ComponentDependency{ServiceID: depID}
+	if groupID, err := strconv.Atoi(depAppID); err == nil {
+		dep.GroupID = groupID
+	}
+	info := withConnectionEnvs(ctx, client, req.TeamAlias, req.AppID, dep)
+	return &dependencyResult{dependency: info, added: true}, nil
+}
+
+// deleteDependency 在已有的依赖中查找被依赖组件后删除。只在已有依赖中匹配，不需要知道被依赖组件所在的应用
+func deleteDependency(ctx context.Context, client *api.Client, req *models.DependencyRequest) (*dependencyResult, error) {
+	existing, err := fetchDependencies(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	dep, err := matchDependency(existing.Data.Bean.Dependencies, strings.TrimSpace(req.DepServiceID), strings.TrimSpace(req.DepAppID))
+	if err != nil {
+		return nil, err
+	}
+	// 删除前查询连接信息，删除后这些变量不再注入
+	info := withConnectionEnvs(ctx, client, req.TeamAlias, req.AppID, *dep)
+
+	path := dependenciesPath(req.TeamAlias, req.AppID, req.ServiceID, dep.ServiceID)
+	logger.Info("删除组件依赖: DELETE %s", path)
+	if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodDelete, path, nil); err != nil {
+		return nil, err
+	}
+	return &dependencyResult{dependency: info}, nil
+}
+
+func dependenciesPath(team, appID, serviceID, depID string) string {
+	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/dependencies", team, appID, serviceID)
+	if depID != "" {
+		path += "/" + depID
+	}
+	return path
+}
+
+func fetchDependencies(ctx context.Context, client *api.Client, team, appID, serviceID string) (*models.DependenciesResponse, error) {
+	return tools.Do[models.DependenciesResponse](ctx, client, http.MethodGet, dependenciesPath(team, appID, serviceID, ""), nil)
+}
+
+// withConnectionEnvs 查询被依赖组件的详情，补全名称并取出它对外提供的连接信息(outer作用域的环境变量)
+func withConnectionEnvs(ctx context.Context, client *api.Client, team, appID string, dep models.ComponentDependency) dependencyInfo {
+	if dep.GroupID != 0 {
+		appID = strconv.Itoa(dep.GroupID)
+	}
+	info := dependencyInfo{component: dep}
+	detail, err := currentDetail(ctx, client, team, appID, dep.ServiceID)
+	if err != nil {
+		logger.Warn("查询被依赖组件 %s 的连接信息失败: %v", dep.ServiceID, err)
+		info.envErr = err
+		return info
+	}
+	if info.component.ServiceCName == "" {
+		info.component.ServiceCName = detail.ServiceCName
+	}
+	for _, env := range detail.Envs {
+		if env.Scope == "outer" {
+			info.envs = append(info.envs, env)
+		}
+	}
+	return info
+}
+
+// matchDependency 按组件ID、名称或英文名称在已有依赖中查找，appInput不为空时还需匹配所在应用的ID或名称
+func matchDependency(deps []models.ComponentDependency, input, appInput string) (*models.ComponentDependency, error) {
+	var matched []*models.ComponentDependency
+	for i := range deps {
+		dep := &deps[i]
+		if appInput != "" && appInput != strconv.Itoa(dep.GroupID) && !strings.EqualFold(appInput, dep.GroupName) {
+			continue
+		}
+		if input == dep.ServiceID || strings.EqualFold(input, dep.ServiceCName) || strings.EqualFold(input, dep.K8sComponentName) {
+			matched = append(matched, dep)
+		}
+	}
+	switch len(matched) {
+	case 1:
+		return matched[0], nil
+	case 0:
+		names := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			names = append(names, dependencyName(dep))
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("组件没有依赖 %s，组件当前没有任何依赖", input)
+		}
+		return nil, fmt.Errorf("组件没有依赖 %s，当前依赖的组件: %s", input, strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("%s 匹配到多个依赖的组件，请改用组件ID或通过dep_app_id指定所在应用", input)
+}
+
+func dependencyName(dep models.ComponentDependency) string {
+	if dep.ServiceCName == "" {
+		return dep.ServiceID
+	}
+	return fmt.Sprintf("%s(%s)", dep.ServiceCName, dep.ServiceID)
+}
+
+// dependencyOutput 依赖关系中另一方组件的输出结构
+func dependencyOutput(dep models.ComponentDependency) map[string]interface{} {
+	output := map[string]interface{}{
+		"组件ID": dep.ServiceID,
+	}
+	if dep.ServiceCName != "" {
+		output["组件名称"] = dep.ServiceCName
+	}
+	if dep.K8sComponentName != "" {
+		output["组件英文名称"] = dep.K8sComponentName
+	}
+	if dep.GroupName != "" {
+		output["所在应用"] = dep.GroupName
+	} else if dep.GroupID != 0 {
+		output["所在应用"] = dep.GroupID
+	}
+	if dep.Status != "" {
+		output["运行状态"] = dep.Status
+	}
+	return output
+}
+
+// connectionEnvsOutput 连接信息的输出结构，敏感变量在reveal为false时打码
+func connectionEnvsOutput(info dependencyInfo, reveal bool) interface{} {
+	if info.envErr != nil {
+		return utils.ErrorMessage("查询连接信息", info.envErr)
+	}
+	envs := make([]map[string]interface{}, 0, len(info.envs))
+	for _, env := range info.envs {
+		item := map[string]interface{}{
+			"变量名": env.AttrName,
+			"变量值": envValue(env, reveal),
+		}
+		if env.Name != "" {
+			item["说明"] = env.Name
+		}
+		envs = append(envs, item)
+	}
+	return envs
+}
+
+// renderDependencies 输出依赖的组件(含注入的连接信息)和依赖该组件的组件
+func renderDependencies(req *models.DependenciesRequest, result *dependenciesResult) (interface{}, error) {
+	dependencies := make([]map[string]interface{}, 0, len(result.dependencies))
+	for _, info := range result.dependencies {
+		output := dependencyOutput(info.component)
+		output["连接信息"] = connectionEnvsOutput(info, req.RevealSecrets)
+		dependencies = append(dependencies, output)
+	}
+	dependents := make([]map[string]interface{}, 0, len(result.dependents))
+	for _, dep := range result.dependents {
+		dependents = append(dependents, dependencyOutput(dep))
+	}
+	return map[string]interface{}{
+		"依赖的组件":  dependencies,
+		"依赖它的组件": dependents,
+		"说明":     "依赖的组件的连接信息会作为环境变量注入本组件，例如Web应用依赖数据库后可以通过这些变量连接数据库",
+	}, nil
+}
+
+// renderDependencyResult 输出添加或删除依赖的结果和受影响的连接信息
+func renderDependencyResult(_ *models.DependencyRequest, result *dependencyResult) (interface{}, error) {
+	output := dependencyOutput(result.dependency.component)
+	envs := connectionEnvsOutput(result.dependency, false)
+	if !result.added {
+		output["结果"] = "已删除依赖"
+		output["不再注入的连接信息"] = envs
+		output["说明"] = "需要重启或滚动更新本组件后依赖变更才能生效"
+		return output, nil
+	}
+	output["结果"] = "已添加依赖"
+	output["注入的连接信息"] = envs
+	if result.dependency.envErr == nil && len(result.dependency.envs) == 0 {
+		output["说明"] = "被依赖组件没有提供连接信息，通常需要先为它的端口开启对内服务。重启本组件后依赖生效"
+	} else {
+		output["说明"] = "需要重启或滚动更新本组件后依赖变更才能生效"
+	}
+	return output, nil
+}
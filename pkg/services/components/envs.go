@@ -0,0 +1,322 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/utils"
+)
+
+// isSecretEnv 判断环境变量是否为敏感信息：被标记为敏感，或变量名看起来是密码、令牌、密钥等
+func isSecretEnv(env models.ComponentEnv) bool {
+	return env.IsSecret || utils.IsSecretName(env.AttrName)
+}
+
+// envValue 返回用于输出的变量值，敏感变量在reveal为false时打码
+func envValue(env models.ComponentEnv, reveal bool) string {
+	if reveal || env.AttrValue == "" || !isSecretEnv(env) {
+		return env.AttrValue
+	}
+	return utils.MaskedValue
+}
+
+// maskEnvs 返回打码后的环境变量副本
+func maskEnvs(envs []models.ComponentEnv) []models.ComponentEnv {
+	masked := make([]models.ComponentEnv, len(envs))
+	for i, env := range envs {
+		env.AttrValue = envValue(env, false)
+		masked[i] = env
+	}
+	return masked
+}
+
+// envResult 单个环境变量的操作结果
+type envResult struct {
+	env models.ComponentEnv
+	// outcome 已新增/已更新/已删除/未变化
+	outcome string
+	err     error
+}
+
+// envsResult 批量设置环境变量的结果
+type envsResult struct {
+	items []envResult
+}
+
+// addEnvTool 新增组件环境变量
+var addEnvTool = tools.Spec[models.AddEnvRequest, envResult]{
+	Name:        "rainbond_add_component_env",
+	Description: "在Rainbond平台中为组件新增环境变量，修改后需要重启或滚动更新组件才能生效",
+	Action:      "新增组件环境变量",
+	Call: func(ctx context.Context, client *api.Client, req *models.AddEnvRequest) (*envResult, error) {
+		envs, err := currentEnvs(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if existing, _ := findEnv(envs, req.AttrName, req.Scope); existing != nil {
+			return nil, fmt.Errorf("作用域%s中已存在环境变量 %s，修改取值请使用 rainbond_update_component_env", req.Scope, req.AttrName)
+		}
+		env := models.ComponentEnv{AttrName: req.AttrName, AttrValue: req.AttrValue, Name: req.Name, Scope: req.Scope, IsChange: true, IsSecret: req.IsSecret}
+		if err := addEnv(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, env); err != nil {
+			return nil, err
+		}
+		return &envResult{env: env, outcome: "已新增"}, nil
+	},
+	Render: renderEnvResult[models.AddEnvRequest],
+}
+
+// updateEnvTool 更新组件环境变量，平台生成的不可更改变量会被拒绝
+var updateEnvTool = tools.Spec[models.UpdateEnvRequest, envResult]{
+	Name:        "rainbond_update_component_env",
+	Description: "在Rainbond平台中更新组件环境变量的取值或显示名称，修改后需要重启或滚动更新组件才能生效",
+	Action:      "更新组件环境变量",
+	Call: func(ctx context.Context, client *api.Client, req *models.UpdateEnvRequest) (*envResult, error) {
+		envs, err := currentEnvs(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := changeableEnv(envs, req.AttrName, req.Scope)
+		if err != nil {
+			return nil, err
+		}
+		env := *existing
+		env.AttrValue = req.AttrValue
+		if req.Name != "" {
+			env.Name = req.Name
+		}
+		if env == *existing {
+			return &envResult{env: env, outcome: "未变化"}, nil
+		}
+		if err := updateEnv(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, env); err != nil {
+			return nil, err
+		}
+		return &envResult{env: env, outcome: "已更新"}, nil
+	},
+	Render: renderEnvResult[models.UpdateEnvRequest],
+}
+
+// deleteEnvTool 删除组件环境变量，平台生成的不可更改变量会被拒绝
+var deleteEnvTool = tools.Spec[models.DeleteEnvRequest, envResult]{
+	Name:        "rainbond_delete_component_env",
+	Description: "在Rainbond平台中删除组件环境变量，修改后需要重启或滚动更新组件才能生效",
+	Action:      "删除组件环境变量",
+	Call: func(ctx context.Context, client *api.Client, req *models.DeleteEnvRequest) (*envResult, error) {
+		envs, err := currentEnvs(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := changeableEnv(envs, req.AttrName, req.Scope)
+		if err != nil {
+			return nil, err
+		}
+		path := envPath(req.TeamAlias, req.AppID, req.ServiceID, existing.AttrName) + "?scope=" + url.QueryEscape(existing.Scope)
+		logger.Info("删除组件环境变量: DELETE %s", path)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodDelete, path, nil); err != nil {
+			return nil, err
+		}
+		return &envResult{env: *existing, outcome: "已删除"}, nil
+	},
+	Render: renderEnvResult[models.DeleteEnvRequest],
+}
+
+// setEnvsTool 批量设置组件环境变量
+var setEnvsTool = tools.Spec[models.SetEnvsRequest, envsResult]{
+	Name:        "rainbond_set_component_envs",
+	Description: "在Rainbond平台中批量设置组件环境变量：已存在的变量更新取值，不存在的新增，未列出的变量保持不变。修改后需要重启或滚动更新组件才能生效",
+	Action:      "批量设置组件环境变量",
+	Validate: func(req *models.SetEnvsRequest) error {
+		if len(req.Envs) == 0 {
+			return errors.New("envs不能为空")
+		}
+		seen := make(map[string]struct{}, len(req.Envs))
+		for _, env := range req.Envs {
+			if _, ok := seen[env.AttrName]; ok {
+				return fmt.Errorf("环境变量 %s 重复", env.AttrName)
+			}
+			seen[env.AttrName] = struct{}{}
+		}
+		return nil
+	},
+	Call: setEnvs,
+	Render: func(_ *models.SetEnvsRequest, result *envsResult) (interface{}, error) {
+		failed := 0
+		items := make([]map[string]interface{}, 0, len(result.items))
+		for _, item := range result.items {
+			info := envInfo(item.env)
+			if item.err != nil {
+				failed++
+				info["结果"] = "失败"
+				info["错误"] = utils.ErrorMessage("设置环境变量", item.err)
+			} else {
+				info["结果"] = item.outcome
+			}
+			items = append(items, info)
+		}
+		return map[string]interface{}{
+			"汇总":   fmt.Sprintf("共%d个环境变量，%d个失败", len(result.items), failed),
+			"变量结果": items,
+			"说明":   "环境变量修改后需要重启或滚动更新组件才能生效",
+		}, nil
+	},
+}
+
+// setEnvs 逐个新增或更新环境变量，单个变量失败不影响其他变量。有不可更改的变量时不执行任何操作
+func setEnvs(ctx context.Context, client *api.Client, req *models.SetEnvsRequest) (*envsResult, error) {
+	envs, err := currentEnvs(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make([]*models.ComponentEnv, len(req.Envs))
+	var problems []string
+	for i, setting := range req.Envs {
+		env, _ := findEnv(envs, setting.AttrName, req.Scope)
+		if env != nil && !env.IsChange {
+			problems = append(problems, fmt.Sprintf("环境变量 %s 由平台生成，不可更改", setting.AttrName))
+		}
+		existing[i] = env
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+
+	result := &envsResult{}
+	for i, setting := range req.Envs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		item := envResult{}
+		if existing[i] == nil {
+			item.env = models.ComponentEnv{AttrName: setting.AttrName, AttrValue: setting.AttrValue, Name: setting.Name, Scope: req.Scope, IsChange: true, IsSecret: setting.IsSecret}
+			item.outcome = "已新增"
+			item.err = addEnv(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, item.env)
+		} else {
+			item.env = *existing[i]
+			item.env.AttrValue = setting.AttrValue
+			if setting.Name != "" {
+				item.env.Name = setting.Name
+			}
+			item.outcome = "未变化"
+			if item.env != *existing[i] {
+				item.outcome = "已更新"
+				item.err = updateEnv(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, item.env)
+			}
+		}
+		if item.err != nil {
+			logger.Warn("设置环境变量 %s 失败: %v", setting.AttrName, item.err)
+		}
+		result.items = append(result.items, item)
+	}
+	return result, nil
+}
+
+// currentEnvs 查询组件当前的环境变量
+func currentEnvs(ctx context.Context, client *api.Client, team, appID, serviceID string) ([]models.ComponentEnv, error) {
+	detail, err := currentDetail(ctx, client, team, appID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return detail.Envs, nil
+}
+
+// findEnv 按变量名查找环境变量，scope为空时匹配任意作用域，同名变量存在于多个作用域时返回错误
+func findEnv(envs []models.ComponentEnv, name, scope string) (*models.ComponentEnv, error) {
+	var matched []*models.ComponentEnv
+	for i := range envs {
+		if envs[i].AttrName == name && (scope == "" || envs[i].Scope == scope) {
+			matched = append(matched, &envs[i])
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matched[0], nil
+	default:
+		return nil, fmt.Errorf("环境变量 %s 同时存在于多个作用域，请通过scope指定inner或outer", name)
+	}
+}
+
+// changeableEnv 查找要修改的环境变量，不存在或不可更改时返回错误
+func changeableEnv(envs []models.ComponentEnv, name, scope string) (*models.ComponentEnv, error) {
+	env, err := findEnv(envs, name, scope)
+	if err != nil {
+		return nil, err
+	}
+	if env == nil {
+		if scope != "" {
+			return nil, fmt.Errorf("作用域%s中没有环境变量 %s", scope, name)
+		}
+		return nil, fmt.Errorf("组件没有环境变量 %s", name)
+	}
+	if !env.IsChange {
+		return nil, fmt.Errorf("环境变量 %s 由平台生成，不可更改", name)
+	}
+	return env, nil
+}
+
+func envPath(team, appID, serviceID, name string) string {
+	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/envs", team, appID, serviceID)
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	return path
+}
+
+func addEnv(ctx context.Context, client *api.Client, team, appID, serviceID string, env models.ComponentEnv) error {
+	path := envPath(team, appID, serviceID, "")
+	logger.Info("新增组件环境变量: POST %s %s", path, env.AttrName)
+	_, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPost, path, map[string]interface{}{
+		"attr_name":  env.AttrName,
+		"attr_value": env.AttrValue,
+		"name":       env.Name,
+		"scope":      env.Scope,
+		"is_secret":  env.IsSecret,
+	})
+	return err
+}
+
+func updateEnv(ctx context.Context, client *api.Client, team, appID, serviceID string, env models.ComponentEnv) error {
+	path := envPath(team, appID, serviceID, env.AttrName)
+	logger.Info("更新组件环境变量: PUT %s", path)
+	_, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPut, path, map[string]interface{}{
+		"attr_value": env.AttrValue,
+		"name":       env.Name,
+		"scope":      env.Scope,
+	})
+	return err
+}
+
+// envInfo 环境变量的输出结构，敏感变量的取值打码
+func envInfo(env models.ComponentEnv) map[string]interface{} {
+	info := map[string]interface{}{
+		"变量名": env.AttrName,
+		"变量值": envValue(env, false),
+		"作用域": env.Scope,
+	}
+	if env.Name != "" {
+		info["显示名称"] = env.Name
+	}
+	return info
+}
+
+// renderEnvResult 输出单个环境变量的操作结果
+func renderEnvResult[Req any](_ *Req, result *envResult) (interface{}, error) {
+	output := envInfo(result.env)
+	output["结果"] = result.outcome
+	if result.outcome == "已删除" {
+		delete(output, "变量值")
+	}
+	if result.outcome != "未变化" {
+		output["说明"] = "环境变量修改后需要重启或滚动更新组件才能生效"
+	}
+	return output, nil
+}
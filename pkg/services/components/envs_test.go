@@ -0,0 +1,39 @@
+package components
+
+import (
+	"testing"
+
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/utils"
+)
+
+// TestEnvValueMasking 验证敏感变量按名称或标记打码，显式要求时显示明文
+func TestEnvValueMasking(t *testing.T) {
+	cases := []struct {
+		env    models.ComponentEnv
+		reveal bool
+		want   string
+	}{
+		{models.ComponentEnv{AttrName: "DB_PASSWORD", AttrValue: "s3cret"}, false, utils.MaskedValue},
+		{models.ComponentEnv{AttrName: "api_token", AttrValue: "abc"}, false, utils.MaskedValue},
+		{models.ComponentEnv{AttrName: "ACCESS_KEY_ID", AttrValue: "abc"}, false, utils.MaskedValue},
+		{models.ComponentEnv{AttrName: "LICENSE", AttrValue: "abc", IsSecret: true}, false, utils.MaskedValue},
+		{models.ComponentEnv{AttrName: "DB_PASSWORD", AttrValue: "s3cret"}, true, "s3cret"},
+		{models.ComponentEnv{AttrName: "DB_PASSWORD", AttrValue: ""}, false, ""},
+		{models.ComponentEnv{AttrName: "MODE", AttrValue: "prod"}, false, "prod"},
+		{models.ComponentEnv{AttrName: "DB_PWD", AttrValue: "abc"}, false, utils.MaskedValue},
+		{models.ComponentEnv{AttrName: "PWD", AttrValue: "/app"}, false, "/app"},
+		{models.ComponentEnv{AttrName: "KEYCLOAK_URL", AttrValue: "http://sso"}, false, "http://sso"},
+		{models.ComponentEnv{AttrName: "MONKEY_MODE", AttrValue: "on"}, false, "on"},
+	}
+	for _, c := range cases {
+		if got := envValue(c.env, c.reveal); got != c.want {
+			t.Errorf("envValue(%s, reveal=%t) = %q，期望 %q", c.env.AttrName, c.reveal, got, c.want)
+		}
+	}
+
+	envs := []models.ComponentEnv{{AttrName: "SECRET", AttrValue: "x"}}
+	if maskEnvs(envs)[0].AttrValue != utils.MaskedValue || envs[0].AttrValue != "x" {
+		t.Errorf("maskEnvs 应返回打码后的副本且不修改原切片")
+	}
+}
@@ -0,0 +1,159 @@
+package components
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// eventOptNames 常见操作类型的中文说明，未列出的类型原样输出
+var eventOptNames = map[string]string{
+	"create":              "创建组件",
+	"build_service":       "构建",
+	"deploy":              "部署",
+	"start_service":       "启动",
+	"stop_service":        "停止",
+	"restart_service":     "重启",
+	"upgrade_service":     "滚动更新",
+	"rollback_service":    "回滚",
+	"delete_service":      "删除组件",
+	"horizontal_service":  "水平伸缩",
+	"vertical_service":    "垂直伸缩",
+	"update_service":      "更新配置",
+	"add_env":             "新增环境变量",
+	"update_env":          "更新环境变量",
+	"delete_env":          "删除环境变量",
+	"add_volume":          "添加存储卷",
+	"update_volume":       "修改存储卷",
+	"delete_volume":       "删除存储卷",
+	"add_port":            "添加端口",
+	"update_port":         "修改端口",
+	"delete_port":         "删除端口",
+	"add_dependency":      "添加依赖",
+	"delete_dependency":   "删除依赖",
+	"share_volume":        "挂载共享存储",
+	"cancel_share_volume": "取消挂载共享存储",
+}
+
+// eventStatusNames 事件执行结果的中文说明
+var eventStatusNames = map[string]string{
+	"success": "成功",
+	"failure": "失败",
+	"timeout": "超时",
+}
+
+// componentEventsTool 分页获取组件的操作事件
+var componentEventsTool = tools.Spec[models.ComponentEventsRequest, models.ComponentEventListResponse]{
+	Name:        "rainbond_list_component_events",
+	Description: "分页获取Rainbond平台中组件的操作事件（构建、部署、重启、伸缩、配置变更等），包含执行结果、操作人和时间，按时间倒序",
+	Action:      "获取组件操作事件",
+	Path: func(req *models.ComponentEventsRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/events?%s",
+			req.TeamAlias, req.AppID, req.ServiceID, pageQuery(req.Page, req.PageSize))
+	},
+	Render: func(req *models.ComponentEventsRequest, resp *models.ComponentEventListResponse) (interface{}, error) {
+		return renderEvents(resp, req.Page, req.PageSize), nil
+	},
+}
+
+// appEventsTool 分页获取应用下所有组件的操作事件
+var appEventsTool = tools.Spec[models.AppEventsRequest, models.ComponentEventListResponse]{
+	Name:        "rainbond_list_app_events",
+	Description: "分页获取Rainbond平台中应用下所有组件的操作事件，包含所属组件、执行结果、操作人和时间，按时间倒序",
+	Action:      "获取应用操作事件",
+	Path: func(req *models.AppEventsRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/events?%s", req.TeamAlias, req.AppID, pageQuery(req.Page, req.PageSize))
+	},
+	Render: func(req *models.AppEventsRequest, resp *models.ComponentEventListResponse) (interface{}, error) {
+		return renderEvents(resp, req.Page, req.PageSize), nil
+	},
+}
+
+// eventLogTool 获取操作事件的日志，构建事件为构建日志
+var eventLogTool = tools.Spec[models.EventLogRequest, models.EventLogResponse]{
+	Name:        "rainbond_get_component_event_log",
+	Description: "获取Rainbond平台中组件操作事件的日志，构建事件返回构建日志，部署等事件返回执行过程，用于排查操作失败的原因",
+	Action:      "获取操作事件日志",
+	Path: func(req *models.EventLogRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/events/%s/log",
+			req.TeamAlias, req.AppID, req.ServiceID, url.PathEscape(req.EventID))
+	},
+	Render: func(req *models.EventLogRequest, resp *models.EventLogResponse) (interface{}, error) {
+		if len(resp.Data.List) == 0 {
+			return fmt.Sprintf("事件 %s 没有日志", req.EventID), nil
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "事件 %s 的日志，共%d行：\n", req.EventID, len(resp.Data.List))
+		for _, line := range resp.Data.List {
+			if line.Time != "" {
+				b.WriteString(line.Time + " ")
+			}
+			b.WriteString(line.Message + "\n")
+		}
+		return b.String(), nil
+	},
+}
+
+func pageQuery(page, pageSize int) string {
+	return url.Values{
+		"page":      {fmt.Sprint(page)},
+		"page_size": {fmt.Sprint(pageSize)},
+	}.Encode()
+}
+
+// renderEvents 把事件列表整理为便于阅读的结构，并附带分页信息
+func renderEvents(resp *models.ComponentEventListResponse, page, pageSize int) map[string]interface{} {
+	events := make([]map[string]interface{}, 0, len(resp.Data.List))
+	for _, event := range resp.Data.List {
+		info := map[string]interface{}{
+			"事件ID": event.EventID,
+			"操作":   eventOptName(event.OptType),
+			"开始时间": event.CreateTime,
+		}
+		switch {
+		case !event.Finished():
+			info["结果"] = "进行中"
+		case eventStatusNames[event.Status] != "":
+			info["结果"] = eventStatusNames[event.Status]
+		default:
+			info["结果"] = event.Status
+		}
+		if event.EndTime != "" {
+			info["结束时间"] = event.EndTime
+		}
+		if event.UserName != "" {
+			info["操作人"] = event.UserName
+		}
+		if event.Message != "" {
+			info["事件信息"] = event.Message
+		}
+		if event.ServiceID != "" {
+			info["组件ID"] = event.ServiceID
+		}
+		if event.ServiceCName != "" {
+			info["组件名称"] = event.ServiceCName
+		}
+		events = append(events, info)
+	}
+
+	output := map[string]interface{}{
+		"事件列表": events,
+		"分页":   models.Pagination{Page: page, PageSize: pageSize, Total: resp.Data.Total},
+	}
+	if page*pageSize < resp.Data.Total {
+		output["说明"] = fmt.Sprintf("还有更多事件，可以指定page为%d查看下一页。查看某个事件的日志请调用 rainbond_get_component_event_log", page+1)
+	} else {
+		output["说明"] = "查看某个事件的日志请调用 rainbond_get_component_event_log"
+	}
+	return output
+}
+
+func eventOptName(optType string) string {
+	if name, ok := eventOptNames[optType]; ok {
+		return name
+	}
+	return optType
+}
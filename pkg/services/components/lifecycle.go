@@ -0,0 +1,255 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/utils"
+)
+
+// componentActionNames 生命周期操作的中文说明
+var componentActionNames = map[string]string{
+	"start":   "启动",
+	"stop":    "停止",
+	"restart": "重启",
+	"upgrade": "滚动更新",
+	"delete":  "删除",
+}
+
+// operateComponentTool 启动、停止、重启或滚动更新组件
+var operateComponentTool = tools.Spec[models.ComponentActionRequest, models.ComponentEventResponse]{
+	Name:        "rainbond_operate_component",
+	Description: "在Rainbond平台中启动、停止、重启或滚动更新组件。操作是异步的，返回事件ID，稍后通过组件详情查看运行状态",
+	Action:      "操作组件",
+	Method:      http.MethodPost,
+	Path: func(req *models.ComponentActionRequest) string {
+		return componentActionPath(req.TeamAlias, req.AppID, req.ServiceID, req.Action)
+	},
+	Body: func(*models.ComponentActionRequest) interface{} {
+		return map[string]interface{}{}
+	},
+	Render: func(req *models.ComponentActionRequest, resp *models.ComponentEventResponse) (interface{}, error) {
+		return map[string]interface{}{
+			"组件ID": req.ServiceID,
+			"操作":   componentActionNames[req.Action],
+			"事件ID": resp.Data.Bean.EventID,
+			"说明":   "操作已提交，通常需要几十秒生效，可以调用 rainbond_get_component_detail 查看运行状态",
+		}, nil
+	},
+}
+
+// deleteComponentTool 删除组件
+var deleteComponentTool = tools.Spec[models.DeleteComponentRequest, map[string]interface{}]{
+	Name:        "rainbond_delete_component",
+	Description: "在Rainbond平台中删除组件，组件的配置和数据将无法恢复。调用前必须向用户确认",
+	Action:      "删除组件",
+	Method:      http.MethodDelete,
+	Destructive: true,
+	Path: func(req *models.DeleteComponentRequest) string {
+		return componentDetailPath(&models.ComponentDetailRequest{TeamAlias: req.TeamAlias, AppID: req.AppID, ServiceID: req.ServiceID})
+	},
+	Render: func(req *models.DeleteComponentRequest, _ *map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"组件ID": req.ServiceID,
+			"操作结果": "组件已删除",
+		}, nil
+	},
+}
+
+// appActionResult 批量操作的结果，按组件记录
+type appActionResult struct {
+	action string
+	items  []appActionItem
+}
+
+type appActionItem struct {
+	component models.ComponentInfo
+	eventID   string
+	err       error
+}
+
+// operateAppComponentsTool 批量启动、停止、重启或滚动更新应用下的组件
+var operateAppComponentsTool = tools.Spec[models.AppComponentsActionRequest, appActionResult]{
+	Name:        "rainbond_operate_app_components",
+	Description: "在Rainbond平台中批量启动、停止、重启或滚动更新应用下的组件，默认操作全部组件，返回每个组件的结果",
+	Action:      "批量操作组件",
+	Call:        operateAppComponents,
+	Render:      renderAppActionResult,
+}
+
+// deleteAppComponentsTool 批量删除应用下的组件
+var deleteAppComponentsTool = tools.Spec[models.DeleteAppComponentsRequest, appActionResult]{
+	Name:        "rainbond_delete_app_components",
+	Description: "在Rainbond平台中批量删除应用下的组件，需要指定service_ids，或设置all为true删除全部组件，组件的配置和数据将无法恢复，返回每个组件的结果。调用前必须向用户确认",
+	Action:      "批量删除组件",
+	Destructive: true,
+	Validate:    validateDeleteAppComponents,
+	Call:        deleteAppComponents,
+	Render: func(_ *models.DeleteAppComponentsRequest, result *appActionResult) (interface{}, error) {
+		return renderAppActionResult(nil, result)
+	},
+}
+
+func componentActionPath(team, appID, serviceID, action string) string {
+	return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/%s", team, appID, serviceID, action)
+}
+
+// operateAppComponents 逐个操作组件，单个组件失败不影响其他组件。指定的组件有无法识别的名称时不执行任何操作
+func operateAppComponents(ctx context.Context, client *api.Client, req *models.AppComponentsActionRequest) (*appActionResult, error) {
+	targets, err := appComponentTargets(ctx, client, req.TeamAlias, req.AppID, req.ServiceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &appActionResult{action: req.Action}
+	for _, component := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := componentActionPath(req.TeamAlias, req.AppID, component.ServiceID, req.Action)
+		logger.Info("批量操作组件: POST %s", path)
+		item := appActionItem{component: component}
+		resp, err := tools.Do[models.ComponentEventResponse](ctx, client, http.MethodPost, path, map[string]interface{}{})
+		if err != nil {
+			logger.Warn("%s组件 %s 失败: %v", componentActionNames[req.Action], component.ServiceID, err)
+			item.err = err
+		} else {
+			item.eventID = resp.Data.Bean.EventID
+		}
+		result.items = append(result.items, item)
+	}
+	return result, nil
+}
+
+// validateDeleteAppComponents 要求显式指定删除范围，避免遗漏service_ids时误删应用下的全部组件
+func validateDeleteAppComponents(req *models.DeleteAppComponentsRequest) error {
+	specified := false
+	for _, id := range req.ServiceIDs {
+		if strings.TrimSpace(id) != "" {
+			specified = true
+			break
+		}
+	}
+	switch {
+	case req.All && len(req.ServiceIDs) > 0:
+		return errors.New("all为true时不能同时指定service_ids")
+	case !req.All && !specified:
+		return errors.New("必须指定要删除的service_ids，删除全部组件时需设置all为true")
+	}
+	return nil
+}
+
+// deleteAppComponents 逐个删除组件，单个组件失败不影响其他组件。指定的组件有无法识别的名称时不删除任何组件
+func deleteAppComponents(ctx context.Context, client *api.Client, req *models.DeleteAppComponentsRequest) (*appActionResult, error) {
+	var inputs []string
+	if !req.All {
+		inputs = req.ServiceIDs
+	}
+	targets, err := appComponentTargets(ctx, client, req.TeamAlias, req.AppID, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &appActionResult{action: "delete"}
+	for _, component := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := componentDetailPath(&models.ComponentDetailRequest{TeamAlias: req.TeamAlias, AppID: req.AppID, ServiceID: component.ServiceID})
+		logger.Info("批量删除组件: DELETE %s", path)
+		item := appActionItem{component: component}
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodDelete, path, nil); err != nil {
+			logger.Warn("删除组件 %s 失败: %v", component.ServiceID, err)
+			item.err = err
+		}
+		result.items = append(result.items, item)
+	}
+	return result, nil
+}
+
+// appComponentTargets 返回批量操作的目标组件：inputs为空时为应用下的全部组件，否则按ID或名称逐个解析，
+// 有无法识别的名称时返回全部错误
+func appComponentTargets(ctx context.Context, client *api.Client, team, appID string, inputs []string) ([]models.ComponentInfo, error) {
+	list, err := tools.Do[models.ComponentListResponse](ctx, client, http.MethodGet,
+		fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components", team, appID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return list.Data.List, nil
+	}
+
+	byID := make(map[string]models.ComponentInfo, len(list.Data.List))
+	for _, component := range list.Data.List {
+		byID[component.ServiceID] = component
+	}
+	var targets []models.ComponentInfo
+	seen := make(map[string]struct{})
+	var problems []string
+	for _, input := range inputs {
+		id, err := tools.ComponentID(ctx, client, team, appID, strings.TrimSpace(input))
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		component, ok := byID[id]
+		if !ok {
+			component = models.ComponentInfo{ServiceID: id}
+		}
+		targets = append(targets, component)
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+	return targets, nil
+}
+
+// renderAppActionResult 输出每个组件的操作结果和汇总
+func renderAppActionResult(_ *models.AppComponentsActionRequest, result *appActionResult) (interface{}, error) {
+	action := componentActionNames[result.action]
+	if len(result.items) == 0 {
+		return "应用下没有组件，未执行任何操作", nil
+	}
+	failed := 0
+	items := make([]map[string]interface{}, 0, len(result.items))
+	for _, item := range result.items {
+		info := map[string]interface{}{
+			"组件ID": item.component.ServiceID,
+		}
+		if item.component.ServiceCName != "" {
+			info["组件名称"] = item.component.ServiceCName
+		}
+		switch {
+		case item.err != nil:
+			failed++
+			info["结果"] = "失败"
+			info["错误"] = utils.ErrorMessage(action+"组件", item.err)
+		case result.action == "delete":
+			info["结果"] = "已删除"
+		default:
+			info["结果"] = "已提交"
+			info["事件ID"] = item.eventID
+		}
+		items = append(items, info)
+	}
+	done := "已提交"
+	if result.action == "delete" {
+		done = "已删除"
+	}
+	return map[string]interface{}{
+		"操作":   action,
+		"汇总":   fmt.Sprintf("共%d个组件，%d个%s，%d个失败", len(result.items), len(result.items)-failed, done, failed),
+		"组件结果": items,
+	}, nil
+}
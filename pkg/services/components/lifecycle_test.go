@@ -0,0 +1,28 @@
+package components
+
+import (
+	"testing"
+
+	"rainmcp/pkg/models"
+)
+
+// TestValidateDeleteAppComponents 验证批量删除必须显式指定组件或设置all
+func TestValidateDeleteAppComponents(t *testing.T) {
+	cases := []struct {
+		req     models.DeleteAppComponentsRequest
+		wantErr bool
+	}{
+		{models.DeleteAppComponentsRequest{}, true},
+		{models.DeleteAppComponentsRequest{ServiceIDs: []string{}}, true},
+		{models.DeleteAppComponentsRequest{ServiceIDs: []string{" ", ""}}, true},
+		{models.DeleteAppComponentsRequest{ServiceIDs: []string{"web"}, All: true}, true},
+		{models.DeleteAppComponentsRequest{ServiceIDs: []string{"web"}}, false},
+		{models.DeleteAppComponentsRequest{All: true}, false},
+	}
+	for _, c := range cases {
+		err := validateDeleteAppComponents(&c.req)
+		if (err != nil) != c.wantErr {
+			t.Errorf("validateDeleteAppComponents(service_ids=%q, all=%t) 错误 = %v，期望出错 %t", c.req.ServiceIDs, c.req.All, err, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// logPollInterval 跟踪日志时查询新日志的间隔
+var logPollInterval = 2 * time.Second
+
+const (
+	// logPollLines 跟踪日志时每次查询的最近行数，两次查询之间的新日志超过该行数时会有遗漏
+	logPollLines = 200
+	// logAnchorLines 用上一次结果末尾的若干行在新结果中定位已推送的位置
+	logAnchorLines = 10
+)
+
+// logsResult 日志查询结果，followed为跟踪期间收到的新日志
+type logsResult struct {
+	lines        []string
+	followed     []string
+	following    bool
+	elapsed      time.Duration
+	reachedLimit bool
+}
+
+// componentLogsTool 获取组件运行日志，可选继续跟踪新日志
+var componentLogsTool = tools.Spec[models.ComponentLogsRequest, logsResult]{
+	Name:        "rainbond_get_component_logs",
+	Description: "获取Rainbond平台中组件最近的运行日志，支持按时间、实例和文本过滤。follow为true时继续跟踪新日志，通过进度通知推送，直到达到时间或行数上限",
+	Action:      "获取组件日志",
+	Validate: func(req *models.ComponentLogsRequest) error {
+		_, err := sinceSeconds(req.Since, time.Now())
+		return err
+	},
+	Call:   componentLogs,
+	Render: renderLogsResult,
+}
+
+// componentLogs 获取最近的日志，需要跟踪时轮询新日志直到达到时间或行数上限
+func componentLogs(ctx context.Context, client *api.Client, req *models.ComponentLogsRequest) (*logsResult, error) {
+	since, err := sinceSeconds(req.Since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := fetchLogs(ctx, client, req, req.Lines, since)
+	if err != nil {
+		return nil, err
+	}
+	result := &logsResult{lines: grepLines(raw, req.Grep)}
+	if !req.Follow {
+		return result, nil
+	}
+
+	// 以不带过滤条件的最近日志作为已推送位置的起点。首次查询按since和lines过滤，
+	// 结果可能为空或与后续查询不重叠，直接作为起点会把旧日志当作新日志推送
+	raw, err = fetchLogs(ctx, client, req, logPollLines, 0)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	deadline := start.Add(time.Duration(req.FollowSeconds) * time.Second)
+	if d, ok := ctx.Deadline(); ok && d.Add(-returnMargin).Before(deadline) {
+		deadline = d.Add(-returnMargin)
+	}
+	result.following = true
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for time.Now().Add(logPollInterval).Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		latest, err := fetchLogs(ctx, client, req, logPollLines, 0)
+		if err != nil {
+			// 查询偶尔失败时下一轮继续，已推送的位置不变
+			logger.Warn("跟踪组件 %s 日志失败: %v", req.ServiceID, err)
+			continue
+		}
+		fresh := grepLines(newLines(raw, latest), req.Grep)
+		raw = latest
+		if len(fresh) == 0 {
+			continue
+		}
+		if remaining := req.MaxLines - len(result.followed); len(fresh) >= remaining {
+			fresh = fresh[:remaining]
+			result.reachedLimit = true
+		}
+		result.followed = append(result.followed, fresh...)
+		tools.Progress(ctx, float64(len(result.followed)), float64(req.MaxLines), strings.Join(fresh, "\n"))
+		if result.reachedLimit {
+			break
+		}
+	}
+	result.elapsed = time.Since(start)
+	return result, nil
+}
+
+func fetchLogs(ctx context.Context, client *api.Client, req *models.ComponentLogsRequest, lines, since int) ([]string, error) {
+	query := url.Values{}
+	query.Set("lines", strconv.Itoa(lines))
+	if since > 0 {
+		query.Set("since_seconds", strconv.Itoa(since))
+	}
+	if req.PodName != "" {
+		query.Set("pod_name", req.PodName)
+	}
+	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/logs?%s", req.TeamAlias, req.AppID, req.ServiceID, query.Encode())
+	resp, err := tools.Do[models.ComponentLogsResponse](ctx, client, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data.List, nil
+}
+
+// sinceSeconds 把时长或RFC3339时间转换为距now的秒数，为空时返回0表示不限制
+func sinceSeconds(since string, now time.Time) (int, error) {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("since必须是正的时长: %s", since)
+		}
+		return int((d + time.Second - 1) / time.Second), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, fmt.Errorf("since格式不正确，应为时长(如 10m、2h)或RFC3339时间(如 2024-01-02T15:04:05+08:00): %s", since)
+	}
+	if !t.Before(now) {
+		return 0, fmt.Errorf("since不能晚于当前时间: %s", since)
+	}
+	return int(now.Sub(t).Round(time.Second) / time.Second), nil
+}
+
+// newLines 返回cur中位于prev之后的新行。以prev末尾的若干行为锚点，在cur中从后向前查找，
+// 查询窗口滑动后cur可能不再包含prev较早的行，找不到时逐步缩短锚点；
+// 始终找不到说明两次查询之间的新日志超过了查询行数，cur全部视为新行
+func newLines(prev, cur []string) []string {
+	size := len(prev)
+	if size > logAnchorLines {
+		size = logAnchorLines
+	}
+	for ; size > 0; size-- {
+		anchor := prev[len(prev)-size:]
+		for end := len(cur); end >= size; end-- {
+			if equalLines(cur[end-size:end], anchor) {
+				return cur[end:]
+			}
+		}
+	}
+	return cur
+}
+
+func equalLines(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// grepLines 保留包含pattern的行，不区分大小写
+func grepLines(lines []string, pattern string) []string {
+	if pattern == "" {
+		return lines
+	}
+	pattern = strings.ToLower(pattern)
+	matched := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.Contains(strings.ToLower(line), pattern) {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+// renderLogsResult 以纯文本输出日志，便于直接阅读
+func renderLogsResult(req *models.ComponentLogsRequest, result *logsResult) (interface{}, error) {
+	var filters []string
+	if req.PodName != "" {
+		filters = append(filters, "实例 "+req.PodName)
+	}
+	if req.Since != "" {
+		filters = append(filters, "时间 "+req.Since)
+	}
+	if req.Grep != "" {
+		filters = append(filters, fmt.Sprintf("包含 %q", req.Grep))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "组件 %s 最近的日志", req.ServiceID)
+	if len(filters) > 0 {
+		fmt.Fprintf(&b, "（%s）", strings.Join(filters, "，"))
+	}
+	if len(result.lines) == 0 {
+		b.WriteString("：没有符合条件的日志\n")
+	} else {
+		fmt.Fprintf(&b, "，共%d行：\n%s\n", len(result.lines), strings.Join(result.lines, "\n"))
+	}
+
+	if result.following {
+		fmt.Fprintf(&b, "\n跟踪新日志%s，收到%d行", result.elapsed.Round(time.Second), len(result.followed))
+		if result.reachedLimit {
+			b.WriteString("，已达到max_lines上限")
+		}
+		if len(result.followed) > 0 {
+			fmt.Fprintf(&b, "：\n%s\n", strings.Join(result.followed, "\n"))
+		} else {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
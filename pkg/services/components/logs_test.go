@@ -0,0 +1,100 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/models"
+)
+
+// TestNewLines 验证跟踪日志时只推送上一次结果之后的新行
+func TestNewLines(t *testing.T) {
+	cases := []struct {
+		name      string
+		prev, cur []string
+		want      []string
+	}{
+		{"有新行", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"没有新行", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"上一次为空", nil, []string{"a"}, []string{"a"}},
+		{"重复行以最后一次出现为准", []string{"x", "ok"}, []string{"x", "ok", "x", "ok", "y"}, []string{"y"}},
+		{"间隔内日志过多", []string{"a", "b"}, []string{"c", "d"}, []string{"c", "d"}},
+	}
+	for _, c := range cases {
+		got := newLines(c.prev, c.cur)
+		if len(got) == 0 && len(c.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: newLines = %v，期望 %v", c.name, got, c.want)
+		}
+	}
+}
+
+// TestSinceSeconds 验证since支持时长和RFC3339时间
+func TestSinceSeconds(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	for since, want := range map[string]int{
+		"":                     0,
+		"10m":                  600,
+		"1.5s":                 2,
+		"2024-01-02T14:30:00Z": 1800,
+	} {
+		got, err := sinceSeconds(since, now)
+		if err != nil || got != want {
+			t.Errorf("sinceSeconds(%q) = %d, %v，期望 %d", since, got, err, want)
+		}
+	}
+	for _, since := range []string{"-5m", "yesterday", "2024-01-02T16:00:00Z"} {
+		if _, err := sinceSeconds(since, now); err == nil {
+			t.Errorf("sinceSeconds(%q) 应返回错误", since)
+		}
+	}
+}
+
+// TestComponentLogsFollowEmptyWindow 验证首次查询的时间窗口内没有日志时，跟踪只推送之后产生的新行
+func TestComponentLogsFollowEmptyWindow(t *testing.T) {
+	interval := logPollInterval
+	logPollInterval = 50 * time.Millisecond
+	defer func() { logPollInterval = interval }()
+
+	var mu sync.Mutex
+	logs := []string{"old 1", "old 2", "old 3"}
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		list := logs
+		if r.URL.Query().Get("since_seconds") != "" {
+			// 最近一段时间内没有新日志
+			list = nil
+		} else if len(logs) < 5 {
+			logs = append(logs, fmt.Sprintf("new %d", len(logs)-2))
+		}
+		body, _ := json.Marshal(map[string]interface{}{"code": 200, "data": map[string]interface{}{"list": list}})
+		w.Write(body)
+	}))
+	defer stub.Close()
+
+	req := &models.ComponentLogsRequest{
+		TeamAlias: "t", AppID: "1", ServiceID: "a1",
+		Lines: 100, Since: "1m", Follow: true, FollowSeconds: 5, MaxLines: 2,
+	}
+	result, err := componentLogs(context.Background(), api.NewClient(stub.URL, "token"), req)
+	if err != nil {
+		t.Fatalf("componentLogs 返回错误: %v", err)
+	}
+	if len(result.lines) != 0 {
+		t.Errorf("时间窗口内不应有日志: %v", result.lines)
+	}
+	if want := []string{"new 1", "new 2"}; !reflect.DeepEqual(result.followed, want) {
+		t.Errorf("跟踪到的新日志 = %v，期望 %v", result.followed, want)
+	}
+}
@@ -0,0 +1,155 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// portActionNames 端口操作的中文说明
+var portActionNames = map[string]string{
+	"open_outer":        "开启对外服务",
+	"close_outer":       "关闭对外服务",
+	"open_inner":        "开启对内服务",
+	"close_inner":       "关闭对内服务",
+	"change_protocol":   "更改协议",
+	"change_port_alias": "更改端口别名",
+}
+
+// portResult 端口操作结果，ports为操作后组件的全部端口及访问地址
+type portResult struct {
+	port   int
+	action string
+	ports  []models.ComponentPortInfo
+	// detailErr 端口操作已成功但查询访问地址失败
+	detailErr error
+}
+
+// updatePortTool 更新组件端口
+var updatePortTool = tools.Spec[models.UpdatePortRequest, portResult]{
+	Name:        "rainbond_update_component_port",
+	Description: "在Rainbond平台中更新组件端口：开关对外/对内服务、更改协议或端口别名，返回操作后的访问地址",
+	Action:      "更新组件端口",
+	Validate:    validateUpdatePort,
+	Call: func(ctx context.Context, client *api.Client, req *models.UpdatePortRequest) (*portResult, error) {
+		body := map[string]interface{}{"action": req.Action}
+		switch req.Action {
+		case "change_protocol":
+			body["protocol"] = req.Protocol
+		case "change_port_alias":
+			body["port_alias"] = req.PortAlias
+			if req.K8sServiceName != "" {
+				body["k8s_service_name"] = req.K8sServiceName
+			}
+		}
+		path := portPath(req.TeamAlias, req.AppID, req.ServiceID, req.Port)
+		logger.Info("更新组件端口: PUT %s action=%s", path, req.Action)
+		if _, err := tools.Do[models.PortResponse](ctx, client, http.MethodPut, path, body); err != nil {
+			return nil, err
+		}
+		return portsAfter(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, req.Port, req.Action), nil
+	},
+	Render: renderPortResult[models.UpdatePortRequest],
+}
+
+// deletePortTool 删除组件端口
+var deletePortTool = tools.Spec[models.DeletePortRequest, portResult]{
+	Name:        "rainbond_delete_component_port",
+	Description: "在Rainbond平台中删除组件端口，返回剩余端口的访问地址",
+	Action:      "删除组件端口",
+	Call: func(ctx context.Context, client *api.Client, req *models.DeletePortRequest) (*portResult, error) {
+		path := portPath(req.TeamAlias, req.AppID, req.ServiceID, req.Port)
+		logger.Info("删除组件端口: DELETE %s", path)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodDelete, path, nil); err != nil {
+			return nil, err
+		}
+		return portsAfter(ctx, client, req.TeamAlias, req.AppID, req.ServiceID, req.Port, "delete"), nil
+	},
+	Render: renderPortResult[models.DeletePortRequest],
+}
+
+// validateUpdatePort 校验操作需要的附加参数
+func validateUpdatePort(req *models.UpdatePortRequest) error {
+	switch req.Action {
+	case "change_protocol":
+		if req.Protocol == "" {
+			return errors.New("action为change_protocol时必须指定protocol，可选值：tcp/udp/http")
+		}
+	case "change_port_alias":
+		if req.PortAlias == "" {
+			return errors.New("action为change_port_alias时必须指定port_alias")
+		}
+	}
+	return nil
+}
+
+func portPath(team, appID, serviceID string, port int) string {
+	return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/ports/%d", team, appID, serviceID, port)
+}
+
+// portsAfter 端口操作成功后查询组件详情，获取操作后的端口和访问地址
+func portsAfter(ctx context.Context, client *api.Client, team, appID, serviceID string, port int, action string) *portResult {
+	result := &portResult{port: port, action: action}
+	detail, err := tools.Do[models.NewComponentDetailResponse](ctx, client, http.MethodGet,
+		componentDetailPath(&models.ComponentDetailRequest{TeamAlias: team, AppID: appID, ServiceID: serviceID}), nil)
+	if err != nil {
+		logger.Warn("端口操作后查询组件详情失败: %v", err)
+		result.detailErr = err
+		return result
+	}
+	result.ports = detail.Data.Bean.Ports
+	return result
+}
+
+// renderPortResult 输出操作结果、目标端口的当前状态和各端口的访问地址
+func renderPortResult[Req any](_ *Req, result *portResult) (interface{}, error) {
+	output := map[string]interface{}{"端口号": result.port}
+	if result.action == "delete" {
+		output["操作结果"] = "端口已删除"
+	} else {
+		output["操作结果"] = portActionNames[result.action] + "成功"
+	}
+	if result.detailErr != nil {
+		output["说明"] = fmt.Sprintf("查询访问地址失败: %v，可以稍后调用 rainbond_get_component_detail 查看", result.detailErr)
+		return output, nil
+	}
+
+	var urls []string
+	ports := make([]map[string]interface{}, 0, len(result.ports))
+	for _, port := range result.ports {
+		if result.action == "delete" && port.ContainerPort == result.port {
+			// 组件详情可能尚未同步删除结果
+			continue
+		}
+		info := map[string]interface{}{
+			"端口号":  port.ContainerPort,
+			"协议":   port.Protocol,
+			"对外服务": port.IsOuterService,
+			"对内服务": port.IsInnerService,
+		}
+		if len(port.AccessUrls) > 0 {
+			info["访问地址"] = port.AccessUrls
+		}
+		if port.ContainerPort == result.port {
+			output["当前状态"] = info
+			urls = port.AccessUrls
+		}
+		ports = append(ports, info)
+	}
+	if result.action == "delete" {
+		output["剩余端口"] = ports
+		return output, nil
+	}
+	if len(urls) > 0 {
+		output["访问地址"] = urls
+	} else {
+		output["说明"] = "该端口当前没有访问地址，需要开启对外服务后才能从外部访问"
+	}
+	return output, nil
+}
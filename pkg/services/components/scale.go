@@ -0,0 +1,143 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// memoryStep 内存配额需要是该值的整数倍(MB)
+const memoryStep = 32
+
+// scaleChange 一项配额的调整前后取值
+type scaleChange struct {
+	name   string
+	before string
+	after  string
+}
+
+// scaleResult 伸缩结果，changes为空表示取值与当前一致，没有调用Rainbond
+type scaleResult struct {
+	serviceID string
+	eventID   string
+	changes   []scaleChange
+}
+
+// scaleReplicasTool 水平伸缩：调整实例数
+var scaleReplicasTool = tools.Spec[models.ScaleReplicasRequest, scaleResult]{
+	Name:        "rainbond_scale_component_replicas",
+	Description: "在Rainbond平台中调整组件的实例数（水平伸缩），返回调整前后的实例数",
+	Action:      "调整组件实例数",
+	Call: func(ctx context.Context, client *api.Client, req *models.ScaleReplicasRequest) (*scaleResult, error) {
+		detail, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		result := &scaleResult{serviceID: req.ServiceID}
+		if detail.MinNode == req.Replicas {
+			return result, nil
+		}
+		result.changes = []scaleChange{{name: "实例数", before: fmt.Sprint(detail.MinNode), after: fmt.Sprint(req.Replicas)}}
+		path := componentActionPath(req.TeamAlias, req.AppID, req.ServiceID, "horizontal")
+		logger.Info("调整组件实例数: POST %s %d -> %d", path, detail.MinNode, req.Replicas)
+		// 请求体是目标实例数而不是增量，重复提交结果相同，可以安全重试
+		resp, err := tools.Do[models.ComponentEventResponse](api.ContextWithIdempotent(ctx), client, http.MethodPost, path, map[string]interface{}{"new_node": req.Replicas})
+		if err != nil {
+			return nil, err
+		}
+		result.eventID = resp.Data.Bean.EventID
+		return result, nil
+	},
+	Render: renderScaleResult[models.ScaleReplicasRequest],
+}
+
+// scaleResourcesTool 垂直伸缩：调整内存和CPU配额
+var scaleResourcesTool = tools.Spec[models.ScaleResourcesRequest, scaleResult]{
+	Name:        "rainbond_scale_component_resources",
+	Description: "在Rainbond平台中调整组件的内存和CPU配额（垂直伸缩），调整后组件会滚动重启，返回调整前后的配额",
+	Action:      "调整组件资源配额",
+	Validate: func(req *models.ScaleResourcesRequest) error {
+		if req.MinMemory == nil && req.MinCPU == nil {
+			return errors.New("min_memory和min_cpu至少需要指定一个")
+		}
+		if req.MinMemory != nil && *req.MinMemory%memoryStep != 0 {
+			return fmt.Errorf("min_memory必须是%d的整数倍，例如 %d", memoryStep, (*req.MinMemory/memoryStep+1)*memoryStep)
+		}
+		return nil
+	},
+	Call: func(ctx context.Context, client *api.Client, req *models.ScaleResourcesRequest) (*scaleResult, error) {
+		detail, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		memory, cpu := detail.MinMemory, detail.MinCPU
+		if req.MinMemory != nil {
+			memory = *req.MinMemory
+		}
+		if req.MinCPU != nil {
+			cpu = *req.MinCPU
+		}
+
+		result := &scaleResult{serviceID: req.ServiceID}
+		if memory != detail.MinMemory {
+			result.changes = append(result.changes, scaleChange{name: "内存配额", before: fmt.Sprintf("%dMB", detail.MinMemory), after: fmt.Sprintf("%dMB", memory)})
+		}
+		if cpu != detail.MinCPU {
+			result.changes = append(result.changes, scaleChange{name: "CPU配额", before: cpuText(detail.MinCPU), after: cpuText(cpu)})
+		}
+		if len(result.changes) == 0 {
+			return result, nil
+		}
+		path := componentActionPath(req.TeamAlias, req.AppID, req.ServiceID, "vertical")
+		logger.Info("调整组件资源配额: POST %s memory=%d cpu=%d", path, memory, cpu)
+		// 请求体是目标配额，重复提交结果相同，可以安全重试
+		resp, err := tools.Do[models.ComponentEventResponse](api.ContextWithIdempotent(ctx), client, http.MethodPost, path, map[string]interface{}{
+			"new_memory": memory,
+			"new_cpu":    cpu,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.eventID = resp.Data.Bean.EventID
+		return result, nil
+	},
+	Render: renderScaleResult[models.ScaleResourcesRequest],
+}
+
+// currentDetail 查询组件当前的配额
+func currentDetail(ctx context.Context, client *api.Client, team, appID, serviceID string) (*models.ComponentDetailInfo, error) {
+	resp, err := tools.Do[models.NewComponentDetailResponse](ctx, client, http.MethodGet,
+		componentDetailPath(&models.ComponentDetailRequest{TeamAlias: team, AppID: appID, ServiceID: serviceID}), nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询组件当前配额失败: %w", err)
+	}
+	return &resp.Data.Bean, nil
+}
+
+func cpuText(cpu int) string {
+	if cpu == 0 {
+		return "不限制"
+	}
+	return fmt.Sprintf("%d毫核", cpu)
+}
+
+// renderScaleResult 输出每项配额调整前后的取值
+func renderScaleResult[Req any](_ *Req, result *scaleResult) (interface{}, error) {
+	output := map[string]interface{}{"组件ID": result.serviceID}
+	if len(result.changes) == 0 {
+		output["说明"] = "目标值与当前配置一致，没有调整"
+		return output, nil
+	}
+	for _, change := range result.changes {
+		output[change.name] = map[string]string{"调整前": change.before, "调整后": change.after}
+	}
+	output["事件ID"] = result.eventID
+	output["说明"] = "调整已提交，组件滚动更新完成后生效，可以调用 rainbond_get_component_detail 查看"
+	return output, nil
+}
@@ -2,471 +2,364 @@ package components
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"rainmcp/pkg/api"
 	"rainmcp/pkg/logger"
 	"rainmcp/pkg/models"
-	"rainmcp/pkg/utils"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/watch"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
-	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/ThinkInAIXYZ/go-mcp/server"
 )
 
 // Service 处理组件相关的API请求
 type Service struct {
-	client *api.Client
+	clients *api.Pool
 }
 
 // NewService 创建一个新的组件服务
-func NewService(client *api.Client) *Service {
+func NewService(clients *api.Pool) *Service {
 	logger.Debug("创建新的组件服务")
 	return &Service{
-		client: client,
+		clients: clients,
 	}
 }
 
-// RegisterTools 注册组件相关的工具
-func RegisterTools(mcpServer *server.Server, service *Service) {
-	// 注册获取组件详情工具
-	componentDetailTool, err := protocol.NewTool(
-		"rainbond_get_component_detail",
-		"获取Rainbond平台中的组件详情",
-		models.ComponentDetailRequest{},
-	)
-	if err != nil {
-		logger.Error("创建组件详情工具失败: %v", err)
-		return
-	}
-	mcpServer.RegisterTool(componentDetailTool, service.handleGetComponentDetail)
-
-	// 注册基于源码创建组件工具
-	createCodeComponentTool, err := protocol.NewTool(
-		"rainbond_create_code_component",
-		"在Rainbond平台中基于源码创建组件",
-		models.CreateCodeComponentRequest{},
-	)
-	if err != nil {
-		logger.Error("创建基于源码的组件创建工具失败: %v", err)
-		return
-	}
-	mcpServer.RegisterTool(createCodeComponentTool, service.handleCreateCodeComponent)
-
-	// 注册获取组件端口列表工具
-	listPortsTool, err := protocol.NewTool(
-		"rainbond_list_component_ports",
-		"获取Rainbond平台中组件的端口列表",
-		models.ListPortsRequest{},
-	)
-	if err != nil {
-		logger.Error("创建组件端口列表工具失败: %v", err)
-		return
-	}
-	mcpServer.RegisterTool(listPortsTool, service.handleListComponentPorts)
-
-	// 注册添加组件端口工具
-	addPortTool, err := protocol.NewTool(
-		"rainbond_add_component_port",
-		"在Rainbond平台中添加组件端口",
-		models.AddPortRequest{},
-	)
-	if err != nil {
-		logger.Error("创建添加组件端口工具失败: %v", err)
-		return
-	}
-	mcpServer.RegisterTool(addPortTool, service.handleAddComponentPort)
-
-	//// 注册更新组件端口工具
-	//updatePortTool, err := protocol.NewTool(
-	//	"rainbond_update_component_port",
-	//	"在Rainbond平台中更新组件端口",
-	//	models.UpdatePortRequest{},
-	//)
-	//if err != nil {
-	//	logger.Error("创建更新组件端口工具失败: %v", err)
-	//	return
-	//}
-	//mcpServer.RegisterTool(updatePortTool, service.handleUpdateComponentPort)
-	//
-	//// 注册删除组件端口工具
-	//deletePortTool, err := protocol.NewTool(
-	//	"rainbond_delete_component_port",
-	//	"在Rainbond平台中删除组件端口",
-	//	models.DeletePortRequest{},
-	//)
-	//if err != nil {
-	//	logger.Error("创建删除组件端口工具失败: %v", err)
-	//	return
-	//}
-	//mcpServer.RegisterTool(deletePortTool, service.handleDeleteComponentPort)
-	//
-	//// 注册构建组件工具
-	//buildServiceTool, err := protocol.NewTool(
-	//	"rainbond_build_component",
-	//	"在Rainbond平台中构建组件",
-	//	models.BuildComponentRequest{},
-	//)
-	//if err != nil {
-	//	logger.Error("创建组件构建工具失败: %v", err)
-	//	return
-	//}
-	//mcpServer.RegisterTool(buildServiceTool, service.handleBuildService)
-
-	// 注册获取应用下组件列表工具
-	listComponentsTool, err := protocol.NewTool(
-		"rainbond_list_components",
-		"获取Rainbond平台中应用下的组件列表",
-		models.ListComponentsRequest{},
-	)
-	if err != nil {
-		logger.Error("创建组件列表工具失败: %v", err)
-		return
-	}
-	mcpServer.RegisterTool(listComponentsTool, service.handleListComponents)
+// componentDetailTool 获取组件详情
+var componentDetailTool = tools.Spec[models.ComponentDetailRequest, models.NewComponentDetailResponse]{
+	Name:        "rainbond_get_component_detail",
+	Description: "获取Rainbond平台中的组件详情",
+	Action:      "获取组件详情",
+	Path:        componentDetailPath,
+	Render:      renderComponentDetail,
 }
 
-// handleListComponents 处理获取应用下组件列表的请求
-func (service *Service) handleListComponents(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-	// 解析请求参数
-	rainTokenValue := ctx.Value(models.RainTokenKey{})
-	rainToken := rainTokenValue.(string)
-	service.client.Token = rainToken
-
-	req := new(models.ListComponentsRequest)
-	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-		// 记录原始错误
-		logger.Error("解析获取组件列表请求失败: %v", err)
+// componentEnvsResource 组件环境变量，来自组件详情，敏感变量的取值打码
+var componentEnvsResource = tools.Spec[models.ComponentDetailRequest, models.NewComponentDetailResponse]{
+	Name:   "component_envs",
+	Action: "获取组件环境变量",
+	Path:   componentDetailPath,
+	Render: func(_ *models.ComponentDetailRequest, resp *models.NewComponentDetailResponse) (interface{}, error) {
+		return maskEnvs(resp.Data.Bean.Envs), nil
+	},
+}
 
-		// 尝试解析原始请求数据
-		var rawData map[string]interface{}
-		var detailedErrMsg string
-		if jsonErr := json.Unmarshal(request.RawArguments, &rawData); jsonErr == nil {
-			// 检查必填字段
-			requiredFields := []string{"team_alias", "app_id"}
-			var missingFields []string
+// componentVolumesResource 组件存储卷，来自组件详情
+var componentVolumesResource = tools.Spec[models.ComponentDetailRequest, models.NewComponentDetailResponse]{
+	Name:   "component_volumes",
+	Action: "获取组件存储卷",
+	Path:   componentDetailPath,
+	Render: func(_ *models.ComponentDetailRequest, resp *models.NewComponentDetailResponse) (interface{}, error) {
+		return resp.Data.Bean.Volumes, nil
+	},
+}
 
-			for _, field := range requiredFields {
-				if _, exists := rawData[field]; !exists {
-					missingFields = append(missingFields, field)
-				}
-			}
+func componentDetailPath(req *models.ComponentDetailRequest) string {
+	return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s", req.TeamAlias, req.AppID, req.ServiceID)
+}
 
-			// 构建详细错误信息
-			if len(missingFields) > 0 {
-				detailedErrMsg = fmt.Sprintf("缺少必填字段: %s", strings.Join(missingFields, ", "))
-			} else {
-				detailedErrMsg = fmt.Sprintf("请求参数验证失败: %v", err)
-			}
-		} else {
-			detailedErrMsg = fmt.Sprintf("解析JSON数据失败: %v", jsonErr)
+// createCodeComponentTool 基于源码创建组件
+var createCodeComponentTool = tools.Spec[models.CreateCodeComponentRequest, map[string]interface{}]{
+	Name:        "rainbond_create_code_component",
+	Description: "在Rainbond平台中基于源码创建组件",
+	Action:      "创建组件",
+	Method:      "POST",
+	Path: func(req *models.CreateCodeComponentRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/create", req.TeamAlias, req.AppID)
+	},
+	Body: func(req *models.CreateCodeComponentRequest) interface{} {
+		requestData := map[string]interface{}{
+			"service_cname": req.ServiceCName,
+			"repo_url":      req.RepoURL,
+			"branch":        req.Branch,
+			// 始终设置 is_deploy 为 true
+			"is_deploy": true,
 		}
-
-		// 返回带有详细错误信息的响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: detailedErrMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 参数校验
-	if req.TeamAlias == "" {
-		errMsg := "缺少必填字段: team_alias"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	if req.AppID == "" {
-		errMsg := "缺少必填字段: app_id"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 构建API路径 - 根据Rainbond OpenAPI文档
-	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components",
-		req.TeamAlias, req.AppID)
-
-	logger.Info("获取应用下组件列表: %s", path)
-
-	// 调用Rainbond API获取组件列表
-	resp, err := service.client.Get(path)
-	if err != nil {
-		errMsg := fmt.Sprintf("获取组件列表失败: %v", err)
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 使用ComponentListResponse结构体解析响应
-	var componentsResp models.ComponentListResponse
-	logger.Debug("原始响应数据: %s", string(resp))
-
-	if err := json.Unmarshal(resp, &componentsResp); err != nil {
-		logger.Warn("解析组件列表响应失败: %v", err)
-
-		// 如果解析失败，尝试解析为通用JSON
-		var result interface{}
-		if err := json.Unmarshal(resp, &result); err != nil {
-			errMsg := fmt.Sprintf("解析组件列表响应失败: %v", err)
-			logger.Error(errMsg)
-			return &protocol.CallToolResult{
-				Content: []protocol.Content{
-					&protocol.TextContent{
-						Type: "text",
-						Text: errMsg,
-					},
-				},
-				IsError: true,
-			}, nil
+		if req.Username != "" {
+			requestData["username"] = req.Username
+		}
+		if req.Password != "" {
+			requestData["password"] = req.Password
 		}
+		return requestData
+	},
+}
 
-		// 将结果转换为格式化的JSON字符串
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			logger.Error("格式化响应数据失败: %v", err)
-			// 如果格式化失败，直接返回原始数据
-			resultJSON = resp
+// createImageComponentTool 基于镜像创建组件
+var createImageComponentTool = tools.Spec[models.CreateImageComponentRequest, models.CreateComponentResponse]{
+	Name:        "rainbond_create_image_component",
+	Description: "在Rainbond平台中基于容器镜像创建组件，可以同时设置启动命令、资源配额、环境变量和端口，返回新组件的ID",
+	Action:      "创建镜像组件",
+	Method:      "POST",
+	Validate:    validateImageComponent,
+	Path: func(req *models.CreateImageComponentRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/image", req.TeamAlias, req.AppID)
+	},
+	Body: func(req *models.CreateImageComponentRequest) interface{} {
+		requestData := map[string]interface{}{
+			"service_cname": req.ServiceCName,
+			"image":         req.Image,
+			"min_memory":    req.MinMemory,
+			"min_cpu":       req.MinCPU,
+			"is_deploy":     req.IsDeploy,
+		}
+		if req.K8sComponentName != "" {
+			requestData["k8s_component_name"] = req.K8sComponentName
+		}
+		if req.Username != "" {
+			requestData["username"] = req.Username
+		}
+		if req.Password != "" {
+			requestData["password"] = req.Password
+		}
+		if req.Cmd != "" {
+			requestData["cmd"] = req.Cmd
+		}
+		if len(req.Envs) > 0 {
+			requestData["envs"] = req.Envs
+		}
+		if len(req.Ports) > 0 {
+			requestData["ports"] = req.Ports
 		}
+		return requestData
+	},
+	Render: func(req *models.CreateImageComponentRequest, resp *models.CreateComponentResponse) (interface{}, error) {
+		component := resp.Data.Bean
+		output := map[string]interface{}{
+			"组件ID": component.ServiceID,
+			"组件名称": component.ServiceCName,
+			"镜像":   req.Image,
+			"立即部署": req.IsDeploy,
+		}
+		if component.K8sComponentName != "" {
+			output["组件英文名称"] = component.K8sComponentName
+		}
+		if req.IsDeploy {
+			output["说明"] = "组件已创建并开始部署，可以调用 rainbond_get_component_detail 查看运行状态和访问地址"
+		} else {
+			output["说明"] = "组件已创建但没有部署，需要时调用 rainbond_build_component 构建并部署"
+		}
+		return output, nil
+	},
+}
 
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: string(resultJSON),
-				},
-			},
-		}, nil
+// validateImageComponent 检查环境变量名和端口是否重复
+func validateImageComponent(req *models.CreateImageComponentRequest) error {
+	envs := make(map[string]struct{}, len(req.Envs))
+	for _, env := range req.Envs {
+		if _, ok := envs[env.Name]; ok {
+			return fmt.Errorf("环境变量 %s 重复", env.Name)
+		}
+		envs[env.Name] = struct{}{}
 	}
-
-	// 成功解析为ComponentListResponse结构体
-	logger.Info("成功解析组件列表数据，共有 %d 个组件", len(componentsResp.Data.List))
-
-	// 将结果转换为包含字段描述的JSON字符串
-	resultJSON, err := utils.MarshalJSONWithDescription(componentsResp)
-	if err != nil {
-		logger.Error("格式化响应数据失败: %v", err)
-		// 如果格式化失败，尝试使用标准JSON序列化
-		resultJSON, err = json.MarshalIndent(componentsResp, "", "  ")
-		if err != nil {
-			logger.Error("标准JSON格式化也失败: %v", err)
-			// 如果标准格式化也失败，直接返回原始数据
-			resultJSON = resp
+	ports := make(map[int]struct{}, len(req.Ports))
+	for _, port := range req.Ports {
+		if _, ok := ports[port.Port]; ok {
+			return fmt.Errorf("端口 %d 重复", port.Port)
 		}
+		ports[port.Port] = struct{}{}
 	}
-
-	// 返回结果
-	return &protocol.CallToolResult{
-		Content: []protocol.Content{
-			&protocol.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return nil
 }
 
-// handleGetComponentDetail 处理获取组件详情的请求
-func (service *Service) handleGetComponentDetail(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-	// 解析请求参数
-	rainTokenValue := ctx.Value(models.RainTokenKey{})
-	rainToken := rainTokenValue.(string)
-	service.client.Token = rainToken
-
-	req := new(models.ComponentDetailRequest)
-	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-		// 记录原始错误
-		logger.Error("解析获取组件详情请求失败: %v", err)
-
-		// 尝试解析原始请求数据
-		var rawData map[string]interface{}
-		var detailedErrMsg string
-		if jsonErr := json.Unmarshal(request.RawArguments, &rawData); jsonErr == nil {
-			// 检查必填字段
-			requiredFields := []string{"team_alias", "app_id", "service_id"}
-			var missingFields []string
-
-			for _, field := range requiredFields {
-				if _, exists := rawData[field]; !exists {
-					missingFields = append(missingFields, field)
-				}
-			}
+// listPortsTool 获取组件端口列表
+var listPortsTool = tools.Spec[models.ListPortsRequest, models.PortListResponse]{
+	Name:        "rainbond_list_component_ports",
+	Description: "获取Rainbond平台中组件的端口列表",
+	Action:      "获取组件端口列表",
+	Path: func(req *models.ListPortsRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/ports", req.TeamAlias, req.AppID, req.ServiceID)
+	},
+	Render: renderPortList,
+}
 
-			// 构建详细错误信息
-			if len(missingFields) > 0 {
-				detailedErrMsg = fmt.Sprintf("缺少必填字段: %s", strings.Join(missingFields, ", "))
-			} else {
-				detailedErrMsg = fmt.Sprintf("请求参数验证失败: %v", err)
-			}
-		} else {
-			detailedErrMsg = fmt.Sprintf("解析JSON数据失败: %v", jsonErr)
+// addPortTool 添加组件端口
+var addPortTool = tools.Spec[models.AddPortRequest, models.PortResponse]{
+	Name:        "rainbond_add_component_port",
+	Description: "在Rainbond平台中添加组件端口",
+	Action:      "添加组件端口",
+	Method:      "POST",
+	Path: func(req *models.AddPortRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/ports", req.TeamAlias, req.AppID, req.ServiceID)
+	},
+	Body: func(req *models.AddPortRequest) interface{} {
+		return map[string]interface{}{
+			"port":             req.Port,
+			"protocol":         req.Protocol,
+			"is_outer_service": req.IsOuterService,
 		}
+	},
+}
 
-		// 返回带有详细错误信息的响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: detailedErrMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
+// listComponentsTool 获取应用下组件列表
+var listComponentsTool = tools.Spec[models.ListComponentsRequest, models.ComponentListResponse]{
+	Name:        "rainbond_list_components",
+	Description: "获取Rainbond平台中应用下的组件列表",
+	Action:      "获取组件列表",
+	Path: func(req *models.ListComponentsRequest) string {
+		return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components", req.TeamAlias, req.AppID)
+	},
+}
 
-	// 参数校验
-	if req.TeamAlias == "" {
-		errMsg := "缺少必填字段: team_alias"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
+// RegisterTools 注册组件相关的工具
+func RegisterTools(mcpServer *server.Server, service *Service, middlewares ...server.ToolMiddleware) {
+	tools.RegisterTyped(mcpServer, service.clients, componentDetailTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, createCodeComponentTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, createImageComponentTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, listPortsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, addPortTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, updatePortTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deletePortTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, buildComponentTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, operateComponentTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, operateAppComponentsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, listDependenciesTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, addDependencyTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deleteDependencyTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, buildVersionsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, rollbackTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, componentEventsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, appEventsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, eventLogTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, componentLogsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, addEnvTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, updateEnvTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deleteEnvTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, setEnvsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, addVolumeTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, resizeVolumeTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deleteVolumeTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, shareVolumeTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, scaleReplicasTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, scaleResourcesTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deleteComponentTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, deleteAppComponentsTool, middlewares...)
+	tools.RegisterTyped(mcpServer, service.clients, listComponentsTool, middlewares...)
+}
 
-	if req.AppID == "" {
-		errMsg := "缺少必填字段: app_id"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
+// componentURI 组件资源的URI模板，子资源在其后追加路径
+const componentURI = "rainbond://teams/{team}/apps/{app_id}/components/{service_id}"
+
+// RegisterResources 注册组件相关的资源
+func RegisterResources(mcpServer *server.Server, service *Service, middlewares ...tools.ResourceMiddleware) {
+	tools.RegisterResource(mcpServer, service.clients, tools.Resource{
+		URI:         "rainbond://teams/{team}/apps/{app_id}/components",
+		Name:        "组件列表",
+		Description: "应用下的组件列表",
+	}, listComponentsTool, middlewares...)
+	tools.RegisterResource(mcpServer, service.clients, tools.Resource{
+		URI:         componentURI,
+		Name:        "组件详情",
+		Description: "组件的运行状态、资源配额、端口、环境变量和存储卷",
+	}, componentDetailTool, middlewares...)
+	tools.RegisterResource(mcpServer, service.clients, tools.Resource{
+		URI:         componentURI + "/ports",
+		Name:        "组件端口",
+		Description: "组件的端口列表",
+	}, listPortsTool, middlewares...)
+	tools.RegisterResource(mcpServer, service.clients, tools.Resource{
+		URI:         componentURI + "/envs",
+		Name:        "组件环境变量",
+		Description: "组件的环境变量列表",
+	}, componentEnvsResource, middlewares...)
+	tools.RegisterResource(mcpServer, service.clients, tools.Resource{
+		URI:         componentURI + "/volumes",
+		Name:        "组件存储卷",
+		Description: "组件的存储卷列表",
+	}, componentVolumesResource, middlewares...)
+}
 
-	if req.ServiceID == "" {
-		errMsg := "缺少必填字段: service_id"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
+// RegisterWatches 注册组件资源的状态查询，订阅组件或应用的会话在状态、实例数或端口变化时收到更新通知
+func RegisterWatches(watcher *watch.Watcher, service *Service) {
+	watcher.Handle(componentURI, probeComponent)
+	watcher.Handle(componentURI+"/ports", probeComponent)
+	watcher.Handle("rainbond://teams/{team}/apps/{app_id}/components", probeApp)
+}
 
-	// 构建API路径 - 根据Rainbond OpenAPI文档
-	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s",
-		req.TeamAlias, req.AppID, req.ServiceID)
+// probeConcurrency 查询应用状态时同时查询组件详情的最大数量
+const probeConcurrency = 4
 
-	logger.Info("获取组件详情: %s", path)
+// probeComponent 组件状态摘要：运行状态、实例数和端口。同一次轮询内订阅了应用和组件时只查询一次
+func probeComponent(ctx context.Context, client *api.Client, args map[string]string) (string, error) {
+	key := strings.Join([]string{args["team"], args["app_id"], args["service_id"]}, "/")
+	return watch.Cached(ctx, key, func() (string, error) {
+		resp, err := componentDetailTool.Fetch(ctx, client, args)
+		if err != nil {
+			return "", err
+		}
+		detail := resp.Data.Bean
+		parts := []string{detail.Status, detail.StatusCN, strconv.Itoa(detail.MinNode)}
+		for _, port := range detail.Ports {
+			parts = append(parts, fmt.Sprintf("%d/%s/%t/%t", port.ContainerPort, port.Protocol, port.IsOuterService, port.IsInnerService))
+		}
+		return strings.Join(parts, ","), nil
+	})
+}
 
-	// 调用Rainbond API获取组件详情
-	resp, err := service.client.Get(path)
+// probeApp 应用状态摘要：应用下每个组件的运行状态、实例数和端口，组件增减同样反映在摘要中。
+// 组件列表只有运行状态，实例数和端口需要查询组件详情，最多probeConcurrency个并发
+func probeApp(ctx context.Context, client *api.Client, args map[string]string) (string, error) {
+	resp, err := listComponentsTool.Fetch(ctx, client, args)
 	if err != nil {
-		errMsg := fmt.Sprintf("获取组件详情失败: %v", err)
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 使用NewComponentDetailResponse结构体解析响应
-	var detailResp models.NewComponentDetailResponse
-	logger.Debug("原始响应数据: %s", string(resp))
-
-	if err := json.Unmarshal(resp, &detailResp); err != nil {
-		logger.Warn("解析组件详情响应失败: %v", err)
-
-		// 如果解析失败，尝试解析为通用JSON
-		var result interface{}
-		if err := json.Unmarshal(resp, &result); err != nil {
-			errMsg := fmt.Sprintf("解析组件详情响应失败: %v", err)
-			logger.Error(errMsg)
-			return &protocol.CallToolResult{
-				Content: []protocol.Content{
-					&protocol.TextContent{
-						Type: "text",
-						Text: errMsg,
-					},
-				},
-				IsError: true,
-			}, nil
+		return "", err
+	}
+	components := resp.Data.List
+	parts := make([]string, len(components))
+	errs := make([]error, len(components))
+	sem := make(chan struct{}, probeConcurrency)
+	var wg sync.WaitGroup
+	for i, component := range components {
+		componentArgs := map[string]string{"service_id": component.ServiceID}
+		for name, value := range args {
+			componentArgs[name] = value
 		}
-
-		// 将结果转换为格式化的JSON字符串
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, serviceID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			digest, err := probeComponent(ctx, client, componentArgs)
+			parts[i], errs[i] = serviceID+"="+digest, err
+		}(i, component.ServiceID)
+	}
+	wg.Wait()
+	for _, err := range errs {
 		if err != nil {
-			logger.Error("格式化响应数据失败: %v", err)
-			// 如果格式化失败，直接返回原始数据
-			resultJSON = resp
+			return "", err
 		}
-
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: string(resultJSON),
-				},
-			},
-		}, nil
 	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";"), nil
+}
 
-	// 成功解析为NewComponentDetailResponse结构体
-	logger.Info("成功解析组件详情数据，组件名称: %s", detailResp.Data.Bean.ServiceCName)
+// renderComponentDetail 把组件详情整理为便于阅读的结构
+func renderComponentDetail(req *models.ComponentDetailRequest, resp *models.NewComponentDetailResponse) (interface{}, error) {
+	detail := resp.Data.Bean
+	logger.Info("成功解析组件详情数据，组件名称: %s", detail.ServiceCName)
 
-	// 创建格式化的结果对象
 	formattedResult := map[string]interface{}{
 		"基本信息": map[string]interface{}{
-			"组件ID":  detailResp.Data.Bean.ServiceID,
-			"组件名称":  detailResp.Data.Bean.ServiceCName,
-			"组件别名":  detailResp.Data.Bean.ServiceAlias,
-			"运行状态":  detailResp.Data.Bean.StatusCN,
-			"更新时间":  detailResp.Data.Bean.UpdateTime,
-			"内存配额":  fmt.Sprintf("%dMB", detailResp.Data.Bean.MinMemory),
-			"CPU配额": fmt.Sprintf("%d毫核", detailResp.Data.Bean.MinCPU),
+			"组件ID":  detail.ServiceID,
+			"组件名称":  detail.ServiceCName,
+			"组件别名":  detail.ServiceAlias,
+			"运行状态":  detail.StatusCN,
+			"更新时间":  detail.UpdateTime,
+			"内存配额":  fmt.Sprintf("%dMB", detail.MinMemory),
+			"CPU配额": fmt.Sprintf("%d毫核", detail.MinCPU),
+			"实例数":   detail.MinNode,
 		},
 	}
 
 	// 添加端口信息
-	if len(detailResp.Data.Bean.Ports) > 0 {
+	if len(detail.Ports) > 0 {
 		ports := make([]map[string]interface{}, 0)
-		for _, port := range detailResp.Data.Bean.Ports {
+		for _, port := range detail.Ports {
 			portInfo := map[string]interface{}{
 				"端口号":  port.ContainerPort,
 				"协议":   port.Protocol,
@@ -482,12 +375,12 @@ func (service *Service) handleGetComponentDetail(ctx context.Context, request *p
 	}
 
 	// 添加环境变量信息
-	if len(detailResp.Data.Bean.Envs) > 0 {
+	if len(detail.Envs) > 0 {
 		envs := make([]map[string]interface{}, 0)
-		for _, env := range detailResp.Data.Bean.Envs {
+		for _, env := range detail.Envs {
 			envInfo := map[string]interface{}{
 				"变量名": env.AttrName,
-				"变量值": env.AttrValue,
+				"变量值": envValue(env, req.RevealSecrets),
 				"作用域": env.Scope,
 				"可更改": env.IsChange,
 			}
@@ -500,791 +393,36 @@ func (service *Service) handleGetComponentDetail(ctx context.Context, request *p
 	}
 
 	// 添加存储卷信息
-	if len(detailResp.Data.Bean.Volumes) > 0 {
+	if len(detail.Volumes) > 0 {
 		volumes := make([]map[string]interface{}, 0)
-		for _, volume := range detailResp.Data.Bean.Volumes {
+		for _, volume := range detail.Volumes {
 			volumeInfo := map[string]interface{}{
 				"存储卷名称": volume.VolumeName,
 				"挂载路径":  volume.VolumePath,
-				"存储容量":  fmt.Sprintf("%dGB", volume.VolumeCapacity),
+				"存储容量":  capacityText(volume.VolumeCapacity),
+			}
+			if name, ok := volumeTypeNames[volume.VolumeType]; ok {
+				volumeInfo["存储类型"] = name
 			}
 			volumes = append(volumes, volumeInfo)
 		}
 		formattedResult["存储卷"] = volumes
 	}
-
-	// 将格式化的结果转换为JSON
-	resultJSON, err := json.MarshalIndent(formattedResult, "", "  ")
-	if err != nil {
-		logger.Error("格式化结果转换为JSON失败: %v", err)
-		// 如果格式化失败，尝试使用包含字段描述的JSON序列化
-		resultJSON, err = utils.MarshalJSONWithDescription(detailResp)
-		if err != nil {
-			logger.Error("带描述的格式化也失败: %v", err)
-			// 如果格式化失败，尝试使用标准JSON序列化
-			resultJSON, err = json.MarshalIndent(detailResp, "", "  ")
-			if err != nil {
-				logger.Error("标准JSON格式化也失败: %v", err)
-				// 如果标准格式化也失败，直接返回原始数据
-				resultJSON = resp
-			}
-		}
-	}
-
-	// 返回结果
-	return &protocol.CallToolResult{
-		Content: []protocol.Content{
-			&protocol.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
-}
-
-// handleCreateCodeComponent 处理基于源码创建组件的请求
-func (service *Service) handleCreateCodeComponent(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-	rainTokenValue := ctx.Value(models.RainTokenKey{})
-	rainToken := rainTokenValue.(string)
-	service.client.Token = rainToken
-	// 解析请求参数
-	req := new(models.CreateCodeComponentRequest)
-	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-		// 记录原始错误
-		logger.Error("解析基于源码创建组件请求失败: %v", err)
-
-		// 尝试解析原始请求数据
-		var rawData map[string]interface{}
-		var detailedErrMsg string
-		if jsonErr := json.Unmarshal(request.RawArguments, &rawData); jsonErr == nil {
-			// 检查必填字段
-			requiredFields := []string{"team_name", "region_name", "app_id", "service_cname", "k8s_component_name", "repo_url", "branch"}
-			var missingFields []string
-
-			for _, field := range requiredFields {
-				if _, exists := rawData[field]; !exists {
-					missingFields = append(missingFields, field)
-				}
-			}
-
-			// 构建详细错误信息
-			if len(missingFields) > 0 {
-				detailedErrMsg = fmt.Sprintf("缺少必填字段: %s", strings.Join(missingFields, ", "))
-			} else {
-				detailedErrMsg = fmt.Sprintf("请求参数验证失败: %v", err)
-			}
-		} else {
-			detailedErrMsg = fmt.Sprintf("解析JSON数据失败: %v", jsonErr)
-		}
-
-		// 返回带有详细错误信息的响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: detailedErrMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 构建 API 路径 - 根据Rainbond OpenAPI文档
-	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/create",
-		req.TeamAlias, req.AppID)
-
-	logger.Info("基于源码创建组件: %s", path)
-
-	// 准备请求数据
-	requestData := map[string]interface{}{
-		"service_cname": req.ServiceCName,
-		"repo_url":      req.RepoURL,
-		"branch":        req.Branch,
-	}
-
-	// 添加可选字段
-	if req.Username != "" {
-		requestData["username"] = req.Username
-	}
-
-	if req.Password != "" {
-		requestData["password"] = req.Password
-	}
-
-	// 始终设置 is_deploy 为 true
-	requestData["is_deploy"] = true
-
-	// 调用Rainbond API创建组件
-	resp, err := service.client.Post(path, requestData)
-	if err != nil {
-		errMsg := fmt.Sprintf("创建组件失败: %v", err)
-		logger.Error(errMsg)
-
-		// 返回错误响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 解析响应
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp, &result); err != nil {
-		errMsg := fmt.Sprintf("解析创建组件响应失败: %v", err)
-		logger.Error(errMsg)
-
-		// 返回错误响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 格式化结果
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		errMsg := fmt.Sprintf("序列化创建组件结果失败: %v", err)
-		logger.Error(errMsg)
-		return nil, fmt.Errorf("序列化创建组件结果失败: %v", err)
-	}
-
-	// 返回结果
-	return &protocol.CallToolResult{
-		Content: []protocol.Content{
-			&protocol.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
-}
-
-// handleListComponentPorts 处理获取组件端口列表的请求
-func (service *Service) handleListComponentPorts(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-	rainTokenValue := ctx.Value(models.RainTokenKey{})
-	rainToken := rainTokenValue.(string)
-	service.client.Token = rainToken
-	// 解析请求参数
-	req := new(models.ListPortsRequest)
-	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-		// 记录原始错误
-		logger.Error("解析获取组件端口列表请求失败: %v", err)
-
-		// 尝试解析原始请求数据
-		var rawData map[string]interface{}
-		var detailedErrMsg string
-		if jsonErr := json.Unmarshal(request.RawArguments, &rawData); jsonErr == nil {
-			// 检查必填字段
-			requiredFields := []string{"team_alias", "app_id", "service_id"}
-			var missingFields []string
-
-			for _, field := range requiredFields {
-				if _, exists := rawData[field]; !exists {
-					missingFields = append(missingFields, field)
-				}
-			}
-
-			// 构建详细错误信息
-			if len(missingFields) > 0 {
-				detailedErrMsg = fmt.Sprintf("缺少必填字段: %s", strings.Join(missingFields, ", "))
-			} else {
-				detailedErrMsg = fmt.Sprintf("请求参数验证失败: %v", err)
-			}
-		} else {
-			detailedErrMsg = fmt.Sprintf("解析JSON数据失败: %v", jsonErr)
-		}
-
-		// 返回带有详细错误信息的响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: detailedErrMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 参数校验
-	if req.TeamAlias == "" {
-		errMsg := "缺少必填字段: team_alias"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	if req.AppID == "" {
-		errMsg := "缺少必填字段: app_id"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	if req.ServiceID == "" {
-		errMsg := "缺少必填字段: service_id"
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 构建API路径
-	apiPath := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/ports",
-		req.TeamAlias, req.AppID, req.ServiceID)
-
-	logger.Info("获取组件端口列表: %s", apiPath)
-
-	// 发送请求
-	resp, err := service.client.Get(apiPath)
-	if err != nil {
-		errMsg := fmt.Sprintf("获取组件端口列表失败: %v", err)
-		logger.Error(errMsg)
-
-		// 返回错误响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 解析响应
-	logger.Debug("原始响应数据: %s", string(resp))
-
-	// 使用PortListResponse结构体解析响应
-	var portListResp models.PortListResponse
-	if err := json.Unmarshal(resp, &portListResp); err != nil {
-		logger.Warn("解析组件端口列表响应失败: %v", err)
-
-		// 如果解析失败，尝试解析为通用JSON
-		var result interface{}
-		if err := json.Unmarshal(resp, &result); err != nil {
-			errMsg := fmt.Sprintf("解析组件端口列表响应失败: %v", err)
-			logger.Error(errMsg)
-			return &protocol.CallToolResult{
-				Content: []protocol.Content{
-					&protocol.TextContent{
-						Type: "text",
-						Text: errMsg,
-					},
-				},
-				IsError: true,
-			}, nil
-		}
-
-		// 将结果转换为格式化的JSON字符串
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			logger.Error("格式化响应数据失败: %v", err)
-			// 如果格式化失败，直接返回原始数据
-			resultJSON = resp
-		}
-
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: string(resultJSON),
-				},
-			},
-		}, nil
-	}
-
-	// 成功解析为PortListResponse结构体
-	logger.Info("成功解析组件端口列表响应，共有 %d 个端口", len(portListResp.Data.List))
-
-	// 创建格式化的结果对象
-	formattedResult := map[string]interface{}{
-		"端口列表": make([]map[string]interface{}, 0),
-	}
-
-	// 添加端口信息
-	if len(portListResp.Data.List) > 0 {
-		ports := make([]map[string]interface{}, 0)
-		for _, port := range portListResp.Data.List {
-			portInfo := map[string]interface{}{
-				"端口号":  port.Port,
-				"协议":   port.Protocol,
-				"对外服务": port.IsOuterService,
-				"对内服务": port.IsInnerService,
-			}
-			ports = append(ports, portInfo)
-		}
-		formattedResult["端口列表"] = ports
-	}
-
-	// 将结果转换为包含字段描述的JSON字符串
-	resultJSON, err := json.MarshalIndent(formattedResult, "", "  ")
-	if err != nil {
-		logger.Error("格式化结果转换为JSON失败: %v", err)
-		// 如果格式化失败，尝试使用包含字段描述的JSON序列化
-		resultJSON, err = utils.MarshalJSONWithDescription(portListResp)
-		if err != nil {
-			logger.Error("带描述的格式化响应数据失败: %v", err)
-			// 如果格式化失败，尝试使用标准JSON序列化
-			resultJSON, err = json.MarshalIndent(portListResp, "", "  ")
-			if err != nil {
-				logger.Error("标准JSON格式化也失败: %v", err)
-				// 如果标准格式化也失败，直接返回原始数据
-				resultJSON = resp
-			}
-		}
-	}
-
-	// 返回结果
-	return &protocol.CallToolResult{
-		Content: []protocol.Content{
-			&protocol.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return formattedResult, nil
 }
 
-// handleAddComponentPort 处理添加组件端口的请求
-func (service *Service) handleAddComponentPort(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-	rainTokenValue := ctx.Value(models.RainTokenKey{})
-	rainToken := rainTokenValue.(string)
-	service.client.Token = rainToken
-	// 解析请求参数
-	req := new(models.AddPortRequest)
-	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-		logger.Error("解析添加组件端口请求失败: %v", err)
-		return nil, fmt.Errorf("无效的添加组件端口请求: %v", err)
-	}
-
-	// 构建API路径
-	apiPath := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/ports",
-		req.TeamAlias, req.AppID, req.ServiceID)
-
-	// 验证协议类型
-	if req.Protocol != "tcp" && req.Protocol != "udp" && req.Protocol != "http" {
-		errMsg := fmt.Sprintf("不支持的协议类型: %s，只支持 tcp/udp/http", req.Protocol)
-		logger.Error(errMsg)
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 准备请求体
-	requestBody := map[string]interface{}{
-		"port":             req.Port,
-		"protocol":         req.Protocol,
-		"is_outer_service": req.IsOuterService,
-	}
-
-	// 发送请求
-	resp, err := service.client.Post(apiPath, requestBody)
-	if err != nil {
-		errMsg := fmt.Sprintf("添加组件端口失败: %v", err)
-		logger.Error(errMsg)
-
-		// 返回错误响应
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: errMsg,
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// 解析响应
-	logger.Debug("原始响应数据: %s", string(resp))
-
-	// 使用PortResponse结构体解析响应
-	var portResp models.PortResponse
-	if err := json.Unmarshal(resp, &portResp); err != nil {
-		logger.Warn("解析添加组件端口响应失败: %v", err)
-
-		// 如果解析失败，尝试解析为通用JSON
-		var result interface{}
-		if err := json.Unmarshal(resp, &result); err != nil {
-			errMsg := fmt.Sprintf("解析添加组件端口响应失败: %v", err)
-			logger.Error(errMsg)
-			return &protocol.CallToolResult{
-				Content: []protocol.Content{
-					&protocol.TextContent{
-						Type: "text",
-						Text: errMsg,
-					},
-				},
-				IsError: true,
-			}, nil
-		}
-
-		// 将结果转换为格式化的JSON字符串
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			logger.Error("格式化响应数据失败: %v", err)
-			// 如果格式化失败，直接返回原始数据
-			resultJSON = resp
-		}
+// renderPortList 把端口列表整理为便于阅读的结构
+func renderPortList(_ *models.ListPortsRequest, resp *models.PortListResponse) (interface{}, error) {
+	logger.Info("成功解析组件端口列表响应，共有 %d 个端口", len(resp.Data.List))
 
-		return &protocol.CallToolResult{
-			Content: []protocol.Content{
-				&protocol.TextContent{
-					Type: "text",
-					Text: string(resultJSON),
-				},
-			},
-		}, nil
+	ports := make([]map[string]interface{}, 0, len(resp.Data.List))
+	for _, port := range resp.Data.List {
+		ports = append(ports, map[string]interface{}{
+			"端口号":  port.Port,
+			"协议":   port.Protocol,
+			"对外服务": port.IsOuterService,
+			"对内服务": port.IsInnerService,
+		})
 	}
-
-	// 成功解析为PortResponse结构体
-	logger.Info("成功解析添加端口响应，端口号: %d", portResp.Data.Bean.ContainerPort)
-
-	// 将结果转换为包含字段描述的JSON字符串
-	resultJSON, err := utils.MarshalJSONWithDescription(portResp)
-	if err != nil {
-		logger.Error("带描述的格式化响应数据失败: %v", err)
-		// 如果格式化失败，尝试使用标准JSON序列化
-		resultJSON, err = json.MarshalIndent(portResp, "", "  ")
-		if err != nil {
-			logger.Error("标准JSON格式化也失败: %v", err)
-			// 如果标准格式化也失败，直接返回原始数据
-			resultJSON = resp
-		}
-	}
-
-	// 返回结果
-	return &protocol.CallToolResult{
-		Content: []protocol.Content{
-			&protocol.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return map[string]interface{}{"端口列表": ports}, nil
 }
-
-//// handleUpdateComponentPort 处理更新组件端口的请求
-//func (service *Service) handleUpdateComponentPort(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-//	// 解析请求参数
-//	req := new(models.UpdatePortRequest)
-//	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-//		logger.Error("解析更新组件端口请求失败: %v", err)
-//		return nil, fmt.Errorf("无效的更新组件端口请求: %v", err)
-//	}
-//
-//	// 构建API路径
-//	apiPath := fmt.Sprintf("/openapi/v1/teams/%s/regions/%s/apps/%s/services/%s/ports/%d",
-//		req.TenantID, req.RegionName, req.AppID, req.ServiceID, req.Port)
-//
-//	// 准备请求体
-//	requestBody := map[string]interface{}{
-//		"action": req.Action,
-//	}
-//
-//	// 根据不同的操作类型添加相应的参数
-//	switch req.Action {
-//	case "open_outer", "close_outer", "open_inner", "close_inner":
-//		// 这些操作只需要action参数，不需要额外的参数
-//		logger.Debug("执行端口操作: %s", req.Action)
-//	case "change_protocol":
-//		if req.Protocol == "" {
-//			errMsg := "更改协议时必须指定协议类型"
-//			logger.Error(errMsg)
-//			return &protocol.CallToolResult{
-//				Content: []protocol.Content{
-//					&protocol.TextContent{
-//						Type: "text",
-//						Text: errMsg,
-//					},
-//				},
-//				IsError: true,
-//			}, nil
-//		}
-//
-//		// 验证协议类型
-//		if req.Protocol != "tcp" && req.Protocol != "udp" && req.Protocol != "http" {
-//			errMsg := fmt.Sprintf("不支持的协议类型: %s，只支持 tcp/udp/http", req.Protocol)
-//			logger.Error(errMsg)
-//			return &protocol.CallToolResult{
-//				Content: []protocol.Content{
-//					&protocol.TextContent{
-//						Type: "text",
-//						Text: errMsg,
-//					},
-//				},
-//				IsError: true,
-//			}, nil
-//		}
-//
-//		requestBody["protocol"] = req.Protocol
-//		logger.Debug("更改端口协议为: %s", req.Protocol)
-//	default:
-//		errMsg := fmt.Sprintf("不支持的操作类型: %s", req.Action)
-//		logger.Error(errMsg)
-//		return &protocol.CallToolResult{
-//			Content: []protocol.Content{
-//				&protocol.TextContent{
-//					Type: "text",
-//					Text: errMsg,
-//				},
-//			},
-//			IsError: true,
-//		}, nil
-//	}
-//	rainTokenValue := ctx.Value(models.RainTokenKey{})
-//	rainToken := rainTokenValue.(string)
-//	service.client.Token = rainToken
-//	// 发送请求
-//	resp, err := service.client.Put(apiPath, requestBody)
-//	if err != nil {
-//		errMsg := fmt.Sprintf("更新组件端口失败: %v", err)
-//		logger.Error(errMsg)
-//
-//		// 返回错误响应
-//		return &protocol.CallToolResult{
-//			Content: []protocol.Content{
-//				&protocol.TextContent{
-//					Type: "text",
-//					Text: errMsg,
-//				},
-//			},
-//			IsError: true,
-//		}, nil
-//	}
-//
-//	// 解析响应
-//	logger.Debug("原始响应数据: %s", string(resp))
-//
-//	// 使用PortResponse结构体解析响应
-//	var portResp models.PortResponse
-//	if err := json.Unmarshal(resp, &portResp); err != nil {
-//		logger.Warn("解析更新组件端口响应失败: %v", err)
-//
-//		// 如果解析失败，尝试解析为通用JSON
-//		var result interface{}
-//		if err := json.Unmarshal(resp, &result); err != nil {
-//			errMsg := fmt.Sprintf("解析更新组件端口响应失败: %v", err)
-//			logger.Error(errMsg)
-//			return &protocol.CallToolResult{
-//				Content: []protocol.Content{
-//					&protocol.TextContent{
-//						Type: "text",
-//						Text: errMsg,
-//					},
-//				},
-//				IsError: true,
-//			}, nil
-//		}
-//
-//		// 将结果转换为格式化的JSON字符串
-//		resultJSON, err := json.MarshalIndent(result, "", "  ")
-//		if err != nil {
-//			logger.Error("格式化响应数据失败: %v", err)
-//			// 如果格式化失败，直接返回原始数据
-//			resultJSON = resp
-//		}
-//
-//		return &protocol.CallToolResult{
-//			Content: []protocol.Content{
-//				&protocol.TextContent{
-//					Type: "text",
-//					Text: string(resultJSON),
-//				},
-//			},
-//		}, nil
-//	}
-//
-//	// 成功解析为PortResponse结构体
-//	logger.Info("成功解析更新端口响应，端口号: %d", portResp.Data.Bean.ContainerPort)
-//
-//	// 将结果转换为包含字段描述的JSON字符串
-//	resultJSON, err := utils.MarshalJSONWithDescription(portResp)
-//	if err != nil {
-//		logger.Error("带描述的格式化响应数据失败: %v", err)
-//		// 如果格式化失败，尝试使用标准JSON序列化
-//		resultJSON, err = json.MarshalIndent(portResp, "", "  ")
-//		if err != nil {
-//			logger.Error("标准JSON格式化也失败: %v", err)
-//			// 如果标准格式化也失败，直接返回原始数据
-//			resultJSON = resp
-//		}
-//	}
-//
-//	// 返回结果
-//	return &protocol.CallToolResult{
-//		Content: []protocol.Content{
-//			&protocol.TextContent{
-//				Type: "text",
-//				Text: string(resultJSON),
-//			},
-//		},
-//	}, nil
-//}
-//
-//// handleDeleteComponentPort 处理删除组件端口的请求
-//func (service *Service) handleDeleteComponentPort(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-//	// 解析请求参数
-//	req := new(models.DeletePortRequest)
-//	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-//		logger.Error("解析删除组件端口请求失败: %v", err)
-//		return nil, fmt.Errorf("无效的删除组件端口请求: %v", err)
-//	}
-//
-//	// 构建API路径
-//	apiPath := fmt.Sprintf("/openapi/v1/teams/%s/regions/%s/apps/%s/services/%s/ports/%d",
-//		req.TenantID, req.RegionName, req.AppID, req.ServiceID, req.Port)
-//
-//	// 发送请求
-//	resp, err := service.client.Delete(apiPath)
-//	if err != nil {
-//		errMsg := fmt.Sprintf("删除组件端口失败: %v", err)
-//		logger.Error(errMsg)
-//
-//		// 返回错误响应
-//		return &protocol.CallToolResult{
-//			Content: []protocol.Content{
-//				&protocol.TextContent{
-//					Type: "text",
-//					Text: errMsg,
-//				},
-//			},
-//			IsError: true,
-//		}, nil
-//	}
-//
-//	// 解析响应
-//	logger.Debug("原始响应数据: %s", string(resp))
-//
-//	// 尝试解析为通用JSON
-//	var result interface{}
-//	if err := json.Unmarshal(resp, &result); err != nil {
-//		errMsg := fmt.Sprintf("解析删除组件端口响应失败: %v", err)
-//		logger.Error(errMsg)
-//		return &protocol.CallToolResult{
-//			Content: []protocol.Content{
-//				&protocol.TextContent{
-//					Type: "text",
-//					Text: errMsg,
-//				},
-//			},
-//			IsError: true,
-//		}, nil
-//	}
-//
-//	logger.Info("成功解析删除端口响应")
-//
-//	// 格式化结果
-//	resultJSON, err := json.MarshalIndent(result, "", "  ")
-//	if err != nil {
-//		errMsg := fmt.Sprintf("序列化删除组件端口结果失败: %v", err)
-//		logger.Error(errMsg)
-//		return nil, fmt.Errorf("序列化删除组件端口结果失败: %v", err)
-//	}
-//
-//	// 返回结果
-//	return &protocol.CallToolResult{
-//		Content: []protocol.Content{
-//			&protocol.TextContent{
-//				Type: "text",
-//				Text: string(resultJSON),
-//			},
-//		},
-//	}, nil
-//}
-//
-//// handleBuildService 处理构建组件的请求
-//func (service *Service) handleBuildService(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
-//	// 解析请求参数
-//	req := new(models.BuildComponentRequest)
-//	if err := protocol.VerifyAndUnmarshal(request.RawArguments, req); err != nil {
-//		logger.Error("解析构建组件请求失败: %v", err)
-//		return nil, fmt.Errorf("无效的组件构建请求: %v", err)
-//	}
-//
-//	// 构建API路径 - 根据Rainbond OpenAPI文档
-//	path := fmt.Sprintf("/openapi/v1/teams/%s/regions/%s/apps/%s/services/%s/build",
-//		req.TeamAlias, req.RegionName, req.AppID, req.ServiceID)
-//
-//	logger.Info("构建组件: %s", path)
-//
-//	// 准备构建参数
-//	buildParams := map[string]interface{}{
-//		"is_deploy":  req.IsDeploy,
-//		"service_id": req.ServiceID,
-//	}
-//
-//	// 如果有构建版本，添加到参数中
-//	if req.BuildVersion != "" {
-//		buildParams["build_version"] = req.BuildVersion
-//	}
-//
-//	// 调用Rainbond API构建组件
-//	resp, err := service.client.Post(path, buildParams)
-//	if err != nil {
-//		logger.Error("构建组件失败: %v", err)
-//		return nil, fmt.Errorf("构建组件失败: %v", err)
-//	}
-//
-//	// 解析响应
-//	var result map[string]interface{}
-//	if err := json.Unmarshal(resp, &result); err != nil {
-//		logger.Error("解析构建结果失败: %v", err)
-//		return nil, fmt.Errorf("解析构建结果失败: %v", err)
-//	}
-//
-//	// 将构建结果转换为JSON字符串
-//	resultJSON, err := json.MarshalIndent(result, "", "  ")
-//	if err != nil {
-//		logger.Error("序列化构建结果失败: %v", err)
-//		return nil, fmt.Errorf("序列化构建结果失败: %v", err)
-//	}
-//
-//	// 返回结果
-//	return &protocol.CallToolResult{
-//		Content: []protocol.Content{
-//			&protocol.TextContent{
-//				Type: "text",
-//				Text: string(resultJSON),
-//			},
-//		},
-//	}, nil
-//}
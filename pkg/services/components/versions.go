@@ -0,0 +1,129 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// rollbackCheckPageSize 回滚前在最近的这些版本中检查目标版本
+const rollbackCheckPageSize = 100
+
+// rollbackResult 回滚结果
+type rollbackResult struct {
+	from    string
+	to      string
+	eventID string
+}
+
+// buildVersionsTool 分页获取组件的构建版本
+var buildVersionsTool = tools.Spec[models.BuildVersionsRequest, models.BuildVersionsResponse]{
+	Name:        "rainbond_list_build_versions",
+	Description: "分页获取Rainbond平台中组件的构建版本（版本号、代码提交、镜像、构建时间和结果），按构建时间倒序并标出当前运行的版本",
+	Action:      "获取组件构建版本",
+	Path: func(req *models.BuildVersionsRequest) string {
+		return buildVersionsPath(req.TeamAlias, req.AppID, req.ServiceID, req.Page, req.PageSize)
+	},
+	Render: renderBuildVersions,
+}
+
+// rollbackTool 回滚组件到指定的构建版本
+var rollbackTool = tools.Spec[models.RollbackRequest, rollbackResult]{
+	Name:        "rainbond_rollback_component",
+	Description: "在Rainbond平台中把组件回滚到指定的构建版本并重新部署，返回事件ID用于跟踪回滚进度",
+	Action:      "回滚组件",
+	Call:        rollbackComponent,
+	Render: func(_ *models.RollbackRequest, result *rollbackResult) (interface{}, error) {
+		output := map[string]interface{}{
+			"事件ID": result.eventID,
+			"回滚版本": result.to,
+			"说明":   "回滚已开始，可以调用 rainbond_list_component_events 查看进度，或使用事件ID调用 rainbond_get_component_event_log 查看日志",
+		}
+		if result.from != "" {
+			output["回滚前版本"] = result.from
+		}
+		return output, nil
+	},
+}
+
+// rollbackComponent 检查目标版本后提交回滚。目标版本不在最近的版本中时交给Rainbond校验
+func rollbackComponent(ctx context.Context, client *api.Client, req *models.RollbackRequest) (*rollbackResult, error) {
+	versions, err := tools.Do[models.BuildVersionsResponse](ctx, client, http.MethodGet,
+		buildVersionsPath(req.TeamAlias, req.AppID, req.ServiceID, 1, rollbackCheckPageSize), nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询构建版本失败: %w", err)
+	}
+	current := versions.Data.Bean.CurrentVersion
+	if current == req.BuildVersion {
+		return nil, fmt.Errorf("组件当前运行的已经是版本 %s", req.BuildVersion)
+	}
+	for _, version := range versions.Data.List {
+		if version.BuildVersion == req.BuildVersion && version.Status == "failure" {
+			return nil, fmt.Errorf("版本 %s 构建失败，不能回滚到该版本", req.BuildVersion)
+		}
+	}
+
+	path := componentActionPath(req.TeamAlias, req.AppID, req.ServiceID, "rollback")
+	logger.Info("回滚组件: POST %s %s -> %s", path, current, req.BuildVersion)
+	resp, err := tools.Do[models.ComponentEventResponse](ctx, client, http.MethodPost, path, map[string]interface{}{
+		"build_version": req.BuildVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rollbackResult{from: current, to: req.BuildVersion, eventID: resp.Data.Bean.EventID}, nil
+}
+
+func buildVersionsPath(team, appID, serviceID string, page, pageSize int) string {
+	return fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/build_versions?%s",
+		team, appID, serviceID, pageQuery(page, pageSize))
+}
+
+// renderBuildVersions 把构建版本整理为便于阅读的结构，并附带分页信息
+func renderBuildVersions(req *models.BuildVersionsRequest, resp *models.BuildVersionsResponse) (interface{}, error) {
+	current := resp.Data.Bean.CurrentVersion
+	versions := make([]map[string]interface{}, 0, len(resp.Data.List))
+	for _, version := range resp.Data.List {
+		info := map[string]interface{}{
+			"构建版本": version.BuildVersion,
+			"构建时间": version.CreateTime,
+			"构建结果": eventStatusNames[version.Status],
+		}
+		if info["构建结果"] == "" {
+			info["构建结果"] = version.Status
+		}
+		if version.CommitHash != "" {
+			info["代码提交"] = version.CommitHash
+		}
+		if version.CommitMsg != "" {
+			info["提交信息"] = version.CommitMsg
+		}
+		if version.ImageURL != "" {
+			info["镜像"] = version.ImageURL
+		}
+		if version.EventID != "" {
+			info["构建事件ID"] = version.EventID
+		}
+		if version.BuildVersion == current {
+			info["当前版本"] = true
+		}
+		versions = append(versions, info)
+	}
+
+	output := map[string]interface{}{
+		"构建版本": versions,
+		"当前版本": current,
+		"分页":   models.Pagination{Page: req.Page, PageSize: req.PageSize, Total: resp.Data.Total},
+	}
+	if req.Page*req.PageSize < resp.Data.Total {
+		output["说明"] = fmt.Sprintf("还有更多版本，可以指定page为%d查看下一页。回滚请调用 rainbond_rollback_component", req.Page+1)
+	} else {
+		output["说明"] = "回滚请调用 rainbond_rollback_component"
+	}
+	return output, nil
+}
@@ -0,0 +1,302 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+)
+
+// volumeTypeNames 存储类型的中文说明
+var volumeTypeNames = map[string]string{
+	"share-file":  "共享存储",
+	"local":       "本地存储",
+	"config-file": "配置文件",
+}
+
+// reservedPaths 不能作为挂载路径的系统目录
+var reservedPaths = map[string]struct{}{
+	"/": {}, "/bin": {}, "/boot": {}, "/dev": {}, "/etc": {}, "/lib": {}, "/lib64": {},
+	"/proc": {}, "/root": {}, "/run": {}, "/sbin": {}, "/sys": {}, "/usr": {}, "/var": {},
+}
+
+// stoppedStatuses 组件处于这些状态时可以安全地删除存储卷
+var stoppedStatuses = map[string]struct{}{
+	"closed":   {},
+	"undeploy": {},
+}
+
+// volumeResult 存储卷操作结果
+type volumeResult struct {
+	volume models.ComponentVolume
+	// before 调整容量前的容量(GB)
+	before  int
+	outcome string
+	// source 共享存储时提供存储的组件ID
+	source string
+}
+
+// addVolumeTool 为组件添加存储卷
+var addVolumeTool = tools.Spec[models.AddVolumeRequest, volumeResult]{
+	Name:        "rainbond_add_component_volume",
+	Description: "在Rainbond平台中为组件添加存储卷（共享存储、本地存储或配置文件），需要重启或滚动更新组件才能生效",
+	Action:      "添加组件存储卷",
+	Validate: func(req *models.AddVolumeRequest) error {
+		if err := checkMountPath(req.VolumePath); err != nil {
+			return err
+		}
+		if req.VolumeType == "config-file" && req.FileContent == "" {
+			return errors.New("volume_type为config-file时必须指定file_content")
+		}
+		if req.VolumeType != "config-file" && req.FileContent != "" {
+			return errors.New("只有volume_type为config-file时才能指定file_content")
+		}
+		return nil
+	},
+	Call: func(ctx context.Context, client *api.Client, req *models.AddVolumeRequest) (*volumeResult, error) {
+		detail, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkVolumeConflict(detail.Volumes, req.VolumeName, req.VolumePath); err != nil {
+			return nil, err
+		}
+		body := map[string]interface{}{
+			"volume_name": req.VolumeName,
+			"volume_path": req.VolumePath,
+			"volume_type": req.VolumeType,
+		}
+		if req.VolumeType == "config-file" {
+			body["file_content"] = req.FileContent
+		} else {
+			body["volume_capacity"] = req.VolumeCapacity
+		}
+		path := volumePath(req.TeamAlias, req.AppID, req.ServiceID, "")
+		logger.Info("添加组件存储卷: POST %s %s", path, req.VolumeName)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPost, path, body); err != nil {
+			return nil, err
+		}
+		return &volumeResult{
+			volume: models.ComponentVolume{
+				VolumeName:     req.VolumeName,
+				VolumePath:     req.VolumePath,
+				VolumeType:     req.VolumeType,
+				VolumeCapacity: req.VolumeCapacity,
+			},
+			outcome: "已添加",
+		}, nil
+	},
+	Render: renderVolumeResult[models.AddVolumeRequest],
+}
+
+// resizeVolumeTool 调整存储卷容量，只允许扩容
+var resizeVolumeTool = tools.Spec[models.ResizeVolumeRequest, volumeResult]{
+	Name:        "rainbond_resize_component_volume",
+	Description: "在Rainbond平台中扩容组件存储卷，返回调整前后的容量",
+	Action:      "调整存储卷容量",
+	Call: func(ctx context.Context, client *api.Client, req *models.ResizeVolumeRequest) (*volumeResult, error) {
+		detail, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		volume, err := findVolume(detail.Volumes, req.VolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if volume.VolumeType == "config-file" {
+			return nil, fmt.Errorf("存储卷 %s 是配置文件，没有容量", req.VolumeName)
+		}
+		result := &volumeResult{volume: *volume, before: volume.VolumeCapacity}
+		if volume.VolumeCapacity == req.VolumeCapacity {
+			result.outcome = "未变化"
+			return result, nil
+		}
+		if volume.VolumeCapacity == 0 {
+			return nil, fmt.Errorf("存储卷 %s 当前不限制容量，不需要扩容", req.VolumeName)
+		}
+		if req.VolumeCapacity < volume.VolumeCapacity {
+			return nil, fmt.Errorf("存储卷只能扩容，%s 当前容量为 %dGB", req.VolumeName, volume.VolumeCapacity)
+		}
+		path := volumePath(req.TeamAlias, req.AppID, req.ServiceID, req.VolumeName)
+		logger.Info("调整存储卷容量: PUT %s %d -> %d", path, volume.VolumeCapacity, req.VolumeCapacity)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPut, path, map[string]interface{}{
+			"volume_capacity": req.VolumeCapacity,
+		}); err != nil {
+			return nil, err
+		}
+		result.volume.VolumeCapacity = req.VolumeCapacity
+		result.outcome = "已扩容"
+		return result, nil
+	},
+	Render: renderVolumeResult[models.ResizeVolumeRequest],
+}
+
+// deleteVolumeTool 删除组件存储卷，组件未停止时需要显式指定force
+var deleteVolumeTool = tools.Spec[models.DeleteVolumeRequest, volumeResult]{
+	Name:        "rainbond_delete_component_volume",
+	Description: "在Rainbond平台中删除组件存储卷，存储卷中的数据无法恢复。组件未停止时拒绝删除，除非指定force",
+	Action:      "删除组件存储卷",
+	Destructive: true,
+	Call: func(ctx context.Context, client *api.Client, req *models.DeleteVolumeRequest) (*volumeResult, error) {
+		detail, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		volume, err := findVolume(detail.Volumes, req.VolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if _, stopped := stoppedStatuses[detail.Status]; !stopped && !req.Force {
+			status := detail.StatusCN
+			if status == "" {
+				status = detail.Status
+			}
+			return nil, fmt.Errorf("组件当前状态为%s，删除正在使用的存储卷可能导致数据丢失或组件异常。请先调用 rainbond_operate_component 停止组件，或确认后指定force为true", status)
+		}
+		path := volumePath(req.TeamAlias, req.AppID, req.ServiceID, req.VolumeName)
+		logger.Info("删除组件存储卷: DELETE %s force=%t", path, req.Force)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodDelete, path, nil); err != nil {
+			return nil, err
+		}
+		return &volumeResult{volume: *volume, outcome: "已删除"}, nil
+	},
+	Render: renderVolumeResult[models.DeleteVolumeRequest],
+}
+
+// shareVolumeTool 挂载同一应用下其他组件的共享存储
+var shareVolumeTool = tools.Spec[models.ShareVolumeRequest, volumeResult]{
+	Name:        "rainbond_share_component_volume",
+	Description: "在Rainbond平台中把同一应用下其他组件的共享存储或配置文件挂载到组件，需要重启或滚动更新组件才能生效",
+	Action:      "挂载共享存储",
+	Validate: func(req *models.ShareVolumeRequest) error {
+		return checkMountPath(req.VolumePath)
+	},
+	Call: func(ctx context.Context, client *api.Client, req *models.ShareVolumeRequest) (*volumeResult, error) {
+		sourceID, err := tools.ComponentID(ctx, client, req.TeamAlias, req.AppID, strings.TrimSpace(req.SourceServiceID))
+		if err != nil {
+			return nil, err
+		}
+		if sourceID == req.ServiceID {
+			return nil, errors.New("提供存储的组件不能是挂载组件本身")
+		}
+		source, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		volume, err := findVolume(source.Volumes, req.VolumeName)
+		if err != nil {
+			return nil, fmt.Errorf("提供存储的组件%s", err.Error())
+		}
+		if volume.VolumeType == "local" {
+			return nil, fmt.Errorf("存储卷 %s 是本地存储，不能被其他组件共享", req.VolumeName)
+		}
+		target, err := currentDetail(ctx, client, req.TeamAlias, req.AppID, req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkVolumeConflict(target.Volumes, "", req.VolumePath); err != nil {
+			return nil, err
+		}
+
+		path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/dep_volumes", req.TeamAlias, req.AppID, req.ServiceID)
+		logger.Info("挂载共享存储: POST %s %s/%s", path, sourceID, req.VolumeName)
+		if _, err := tools.Do[map[string]interface{}](ctx, client, http.MethodPost, path, map[string]interface{}{
+			"dep_service_id": sourceID,
+			"volume_name":    req.VolumeName,
+			"volume_path":    req.VolumePath,
+		}); err != nil {
+			return nil, err
+		}
+		shared := *volume
+		shared.VolumePath = req.VolumePath
+		return &volumeResult{volume: shared, outcome: "已挂载", source: sourceID}, nil
+	},
+	Render: renderVolumeResult[models.ShareVolumeRequest],
+}
+
+func volumePath(team, appID, serviceID, name string) string {
+	p := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components/%s/volumes", team, appID, serviceID)
+	if name != "" {
+		p += "/" + url.PathEscape(name)
+	}
+	return p
+}
+
+// checkMountPath 挂载路径不能是系统目录
+func checkMountPath(mountPath string) error {
+	if _, ok := reservedPaths[path.Clean(mountPath)]; ok {
+		return fmt.Errorf("挂载路径 %s 是系统目录，不能作为挂载路径", mountPath)
+	}
+	return nil
+}
+
+// checkVolumeConflict 检查组件上是否已有同名存储卷或相同的挂载路径，name为空时只检查挂载路径
+func checkVolumeConflict(volumes []models.ComponentVolume, name, mountPath string) error {
+	for _, volume := range volumes {
+		if name != "" && volume.VolumeName == name {
+			return fmt.Errorf("组件已有名为 %s 的存储卷", name)
+		}
+		if path.Clean(volume.VolumePath) == path.Clean(mountPath) {
+			return fmt.Errorf("挂载路径 %s 已被存储卷 %s 使用", mountPath, volume.VolumeName)
+		}
+	}
+	return nil
+}
+
+// findVolume 按名称查找存储卷，不存在时返回错误并列出已有的存储卷
+func findVolume(volumes []models.ComponentVolume, name string) (*models.ComponentVolume, error) {
+	names := make([]string, 0, len(volumes))
+	for i := range volumes {
+		if volumes[i].VolumeName == name {
+			return &volumes[i], nil
+		}
+		names = append(names, volumes[i].VolumeName)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("没有存储卷 %s，组件当前没有存储卷", name)
+	}
+	return nil, fmt.Errorf("没有存储卷 %s，已有的存储卷: %s", name, strings.Join(names, ", "))
+}
+
+func capacityText(capacity int) string {
+	if capacity == 0 {
+		return "不限制"
+	}
+	return fmt.Sprintf("%dGB", capacity)
+}
+
+// renderVolumeResult 输出存储卷操作结果
+func renderVolumeResult[Req any](_ *Req, result *volumeResult) (interface{}, error) {
+	volume := result.volume
+	output := map[string]interface{}{
+		"存储卷名称": volume.VolumeName,
+		"挂载路径":  volume.VolumePath,
+		"结果":    result.outcome,
+	}
+	if name, ok := volumeTypeNames[volume.VolumeType]; ok {
+		output["存储类型"] = name
+	}
+	if volume.VolumeType != "config-file" {
+		output["存储容量"] = capacityText(volume.VolumeCapacity)
+	}
+	switch result.outcome {
+	case "已扩容":
+		output["存储容量"] = map[string]string{"调整前": capacityText(result.before), "调整后": capacityText(volume.VolumeCapacity)}
+	case "已删除":
+		delete(output, "存储容量")
+	case "已挂载":
+		output["提供存储的组件ID"] = result.source
+	}
+	if result.outcome != "未变化" {
+		output["说明"] = "存储卷变更需要重启或滚动更新组件才能生效"
+	}
+	return output, nil
+}
@@ -7,17 +7,21 @@ import (
 	"rainmcp/pkg/services/components"
 	"rainmcp/pkg/services/regions"
 	"rainmcp/pkg/services/teams"
+	"rainmcp/pkg/tools"
+	"rainmcp/pkg/watch"
 
 	"github.com/ThinkInAIXYZ/go-mcp/server"
 )
 
 // Manager 管理所有Rainbond服务
 type Manager struct {
-	APIClient        *api.Client
+	APIClients       *api.Pool
 	TeamService      *teams.Service
 	RegionService    *regions.Service
 	AppService       *apps.Service
 	ComponentService *components.Service
+	// Watcher 轮询会话订阅的资源并推送更新通知
+	Watcher *watch.Watcher
 }
 
 // NewManager 创建一个新的服务管理器
@@ -30,24 +34,26 @@ func NewManager(apiURL string) *Manager {
 		apiURL = "https://rainbond-api.example.com" // 设置一个默认值以避免错误
 	}
 
-	logger.Info("[Manager] 创建 API 客户端...")
-	client := api.NewClient(apiURL)
+	logger.Info("[Manager] 创建 API 客户端池...")
+	clients := api.NewPool(apiURL)
 
 	logger.Info("[Manager] 初始化各个服务...")
 	manager := &Manager{
-		APIClient:        client,
-		TeamService:      teams.NewService(client),
-		RegionService:    regions.NewService(client),
-		AppService:       apps.NewService(client),
-		ComponentService: components.NewService(client),
+		APIClients:       clients,
+		TeamService:      teams.NewService(clients),
+		RegionService:    regions.NewService(clients),
+		AppService:       apps.NewService(clients),
+		ComponentService: components.NewService(clients),
+		Watcher:          watch.New(clients, watch.DefaultInterval),
 	}
+	components.RegisterWatches(manager.Watcher, manager.ComponentService)
 
 	logger.Info("[Manager] 服务管理器初始化完成")
 	return manager
 }
 
 // RegisterTeamTools 注册团队相关工具
-func RegisterTeamTools(mcpServer *server.Server, manager *Manager) {
+func RegisterTeamTools(mcpServer *server.Server, manager *Manager, middlewares ...server.ToolMiddleware) {
 	logger.Info("[Manager] 注册团队相关工具...")
 
 	// 验证参数
@@ -61,12 +67,12 @@ func RegisterTeamTools(mcpServer *server.Server, manager *Manager) {
 		return
 	}
 
-	teams.RegisterTools(mcpServer, manager.TeamService)
+	teams.RegisterTools(mcpServer, manager.TeamService, middlewares...)
 	logger.Info("[Manager] 团队相关工具注册完成")
 }
 
 // RegisterRegionTools 注册集群相关工具
-func RegisterRegionTools(mcpServer *server.Server, manager *Manager) {
+func RegisterRegionTools(mcpServer *server.Server, manager *Manager, middlewares ...server.ToolMiddleware) {
 	logger.Info("[Manager] 注册集群相关工具...")
 
 	// 验证参数
@@ -81,12 +87,12 @@ func RegisterRegionTools(mcpServer *server.Server, manager *Manager) {
 	}
 
 	logger.Info("[Manager] RegionService.client.BaseURL = %s", manager.RegionService.GetBaseURL())
-	regions.RegisterTools(mcpServer, manager.RegionService)
+	regions.RegisterTools(mcpServer, manager.RegionService, middlewares...)
 	logger.Info("[Manager] 集群相关工具注册完成")
 }
 
 // RegisterAppTools 注册应用相关工具
-func RegisterAppTools(mcpServer *server.Server, manager *Manager) {
+func RegisterAppTools(mcpServer *server.Server, manager *Manager, middlewares ...server.ToolMiddleware) {
 	logger.Info("[Manager] 注册应用相关工具...")
 
 	// 验证参数
@@ -100,12 +106,12 @@ func RegisterAppTools(mcpServer *server.Server, manager *Manager) {
 		return
 	}
 
-	apps.RegisterTools(mcpServer, manager.AppService)
+	apps.RegisterTools(mcpServer, manager.AppService, middlewares...)
 	logger.Info("[Manager] 应用相关工具注册完成")
 }
 
 // RegisterComponentTools 注册组件相关工具
-func RegisterComponentTools(mcpServer *server.Server, manager *Manager) {
+func RegisterComponentTools(mcpServer *server.Server, manager *Manager, middlewares ...server.ToolMiddleware) {
 	logger.Info("[Manager] 注册组件相关工具...")
 
 	// 验证参数
@@ -119,6 +125,23 @@ func RegisterComponentTools(mcpServer *server.Server, manager *Manager) {
 		return
 	}
 
-	components.RegisterTools(mcpServer, manager.ComponentService)
+	components.RegisterTools(mcpServer, manager.ComponentService, middlewares...)
 	logger.Info("[Manager] 组件相关工具注册完成")
 }
+
+// RegisterResources 注册所有资源，资源内容与对应的查询工具一致
+func RegisterResources(mcpServer *server.Server, manager *Manager, middlewares ...tools.ResourceMiddleware) {
+	logger.Info("[Manager] 注册资源...")
+
+	// 验证参数
+	if manager == nil {
+		logger.Error("[Manager] 错误: 服务管理器为空")
+		return
+	}
+
+	teams.RegisterResources(mcpServer, manager.TeamService, middlewares...)
+	regions.RegisterResources(mcpServer, manager.RegionService, middlewares...)
+	apps.RegisterResources(mcpServer, manager.AppService, middlewares...)
+	components.RegisterResources(mcpServer, manager.ComponentService, middlewares...)
+	logger.Info("[Manager] 资源注册完成")
+}
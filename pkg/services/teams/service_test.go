@@ -0,0 +1,86 @@
+package teams
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/models"
+	"rainmcp/pkg/tools"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// TestHandleTeamsListSessionIsolation 模拟多个会话并发调用，验证每个会话只使用自己的令牌
+func TestHandleTeamsListSessionIsolation(t *testing.T) {
+	// 替身Rainbond API：把收到的Authorization原样作为团队名称返回
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"code":200,"msg":"success","msg_show":"","data":{"bean":null,"list":[{"team_alias":%q}]}}`, token)
+	}))
+	defer stub.Close()
+
+	handleTeamsList := tools.NewHandler(api.NewPool(stub.URL), teamsListTool)
+
+	const sessions = 50
+	const callsPerSession = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions*callsPerSession)
+	for i := 0; i < sessions; i++ {
+		token := fmt.Sprintf("session-token-%03d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.WithValue(context.Background(), models.RainTokenKey{}, token)
+			for j := 0; j < callsPerSession; j++ {
+				result, err := handleTeamsList(ctx, &protocol.CallToolRequest{Name: "rainbond_teams"})
+				if err != nil {
+					errs <- fmt.Errorf("%s: %v", token, err)
+					return
+				}
+				text := result.Content[0].(*protocol.TextContent).Text
+				if !strings.Contains(text, `"`+token+`"`) {
+					errs <- fmt.Errorf("%s: 响应中未包含自身令牌: %s", token, text)
+					return
+				}
+				if strings.Count(text, "session-token-") != 1 {
+					errs <- fmt.Errorf("%s: 响应中出现其他会话的令牌: %s", token, text)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestHandleTeamsListWithoutToken 验证缺少令牌时不会发出请求
+func TestHandleTeamsListWithoutToken(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("缺少令牌时不应请求Rainbond API: %s", r.URL.Path)
+	}))
+	defer stub.Close()
+
+	handleTeamsList := tools.NewHandler(api.NewPool(stub.URL), teamsListTool)
+	result, err := handleTeamsList(context.Background(), &protocol.CallToolRequest{Name: "rainbond_teams"})
+	if err != nil {
+		t.Fatalf("缺少令牌时应返回错误结果而不是Go错误: %v", err)
+	}
+	if result == nil || !result.IsError {
+		t.Fatal("缺少令牌时应返回IsError结果")
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// DefaultToolTimeout 未单独配置的工具调用超时时间
+const DefaultToolTimeout = 30 * time.Second
+
+// defaultToolTimeouts 内置的工具超时时间，耗时较长的工具在这里单独放宽
+var defaultToolTimeouts = map[string]time.Duration{
+	"rainbond_create_code_component":  2 * time.Minute,
+	"rainbond_create_image_component": 2 * time.Minute,
+	"rainbond_operate_app_components": 2 * time.Minute,
+	"rainbond_delete_app_components":  2 * time.Minute,
+	// 等待构建结束时最长等待30分钟
+	"rainbond_build_component": 31 * time.Minute,
+	// 跟踪日志时最长跟踪10分钟
+	"rainbond_get_component_logs": 11 * time.Minute,
+}
+
+// ToolTimeouts 工具调用的超时配置
+type ToolTimeouts struct {
+	// Default 未单独配置的工具使用的超时时间
+	Default time.Duration
+	// PerTool 按工具名称配置的超时时间
+	PerTool map[string]time.Duration
+}
+
+// NewToolTimeouts 创建超时配置，overrides格式为 "工具名=时长,工具名=时长"，例如 "rainbond_create_component=5m"
+func NewToolTimeouts(defaultTimeout time.Duration, overrides string) (*ToolTimeouts, error) {
+	if defaultTimeout <= 0 {
+		return nil, fmt.Errorf("默认工具超时时间必须大于0: %s", defaultTimeout)
+	}
+
+	timeouts := &ToolTimeouts{
+		Default: defaultTimeout,
+		PerTool: make(map[string]time.Duration, len(defaultToolTimeouts)),
+	}
+	for name, timeout := range defaultToolTimeouts {
+		timeouts.PerTool[name] = timeout
+	}
+
+	for _, item := range strings.Split(overrides, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("工具超时配置格式错误: %q，应为 工具名=时长", item)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("工具 %s 的超时时间无效: %q", name, value)
+		}
+		timeouts.PerTool[strings.TrimSpace(name)] = timeout
+	}
+	return timeouts, nil
+}
+
+// For 返回指定工具的超时时间
+func (t *ToolTimeouts) For(name string) time.Duration {
+	if timeout, ok := t.PerTool[name]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// Middleware 返回一个工具中间件，为每次工具调用设置截止时间，并在客户端取消或断开时中止调用
+func (t *ToolTimeouts) Middleware() server.ToolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+			timeout := t.For(req.Name)
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			// 传输层会屏蔽HTTP请求的取消，客户端断开需要单独监听
+			if disconnected := api.DisconnectFromContext(ctx); disconnected != nil {
+				go func() {
+					select {
+					case <-disconnected:
+						logger.Info("[工具] 客户端已断开，取消工具调用: %s", req.Name)
+						cancel()
+					case <-ctx.Done():
+					}
+				}()
+			}
+
+			result, err := next(ctx, req)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				if errors.Is(ctxErr, context.DeadlineExceeded) {
+					logger.Warn("[工具] 工具调用超时: %s, 超时时间: %s", req.Name, timeout)
+					return &protocol.CallToolResult{
+						Content: []protocol.Content{
+							&protocol.TextContent{
+								Type: "text",
+								Text: fmt.Sprintf("工具 %s 执行超时（%s），请稍后重试或调大超时时间", req.Name, timeout),
+							},
+						},
+						IsError: true,
+					}, nil
+				}
+				logger.Info("[工具] 工具调用已取消: %s", req.Name)
+				return nil, fmt.Errorf("工具调用已取消: %w", ctxErr)
+			}
+			return result, err
+		}
+	}
+}
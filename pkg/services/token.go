@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/tools"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// TokenToolMiddleware 返回一个工具中间件，把固定的访问令牌写入每次工具调用的上下文。
+// 用于stdio模式：请求不经过HTTP认证，令牌来自启动进程的环境变量
+func TokenToolMiddleware(token string) server.ToolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+			return next(api.ContextWithToken(ctx, token), req)
+		}
+	}
+}
+
+// TokenResourceMiddleware 与TokenToolMiddleware相同，用于资源读取
+func TokenResourceMiddleware(token string) tools.ResourceMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+			return next(api.ContextWithToken(ctx, token), req)
+		}
+	}
+}
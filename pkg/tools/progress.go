@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"context"
+
+	"rainmcp/pkg/logger"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// progressKey 上下文中保存发送进度通知的MCP服务器
+type progressKey struct{}
+
+// withProgress 让处理函数可以通过Progress发送进度通知
+func withProgress(mcpServer *server.Server, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return next(context.WithValue(ctx, progressKey{}, mcpServer), request)
+	}
+}
+
+// Progress 报告耗时较长的工具调用的进度，total为0表示总量未知。
+// 客户端调用工具时没有携带progressToken时不发送，发送失败不影响工具调用
+func Progress(ctx context.Context, progress, total float64, message string) {
+	mcpServer, ok := ctx.Value(progressKey{}).(*server.Server)
+	if !ok {
+		return
+	}
+	if err := mcpServer.SendProgressNotification(ctx, protocol.NewProgressNotification(progress, total, message)); err != nil {
+		logger.Debug("发送进度通知失败: %v", err)
+	}
+}
@@ -0,0 +1,481 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/models"
+)
+
+// 名称解析的对象类型，对应参数结构体的resolve标签
+const (
+	ResolveTeam      = "team"
+	ResolveRegion    = "region"
+	ResolveApp       = "app"
+	ResolveComponent = "component"
+)
+
+// DefaultResolveTTL 列表结果的缓存时间
+const DefaultResolveTTL = time.Minute
+
+// resolveHints 追加到字段说明中，告诉模型可以直接填写名称
+var resolveHints = map[string]string{
+	ResolveTeam:      "，也可以填写团队名称",
+	ResolveApp:       "，也可以填写应用名称",
+	ResolveComponent: "，也可以填写组件名称或组件英文名称",
+}
+
+var kindNames = map[string]string{
+	ResolveTeam:      "团队",
+	ResolveRegion:    "集群",
+	ResolveApp:       "应用",
+	ResolveComponent: "组件",
+}
+
+var (
+	appIDPattern       = regexp.MustCompile(`^[0-9]+$`)
+	componentIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+// Candidate 名称解析时的候选对象
+type Candidate struct {
+	// ID 解析结果，即API路径中使用的标识
+	ID string
+	// Name 展示名称
+	Name string
+	// Extra 区分同名对象的附加信息，例如所在集群
+	Extra string
+	// aliases 除ID外可以匹配的名称
+	aliases []string
+}
+
+func (c Candidate) String() string {
+	s := fmt.Sprintf("%s (ID: %s", c.Name, c.ID)
+	if c.Extra != "" {
+		s += ", " + c.Extra
+	}
+	return s + ")"
+}
+
+// Contains 判断ID或任一名称是否包含value，忽略大小写，value为空时总是返回true
+func (c Candidate) Contains(value string) bool {
+	value = strings.ToLower(value)
+	for _, s := range append([]string{c.ID, c.Name}, c.aliases...) {
+		if strings.Contains(strings.ToLower(s), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// AmbiguousError 表示名称匹配到多个对象
+type AmbiguousError struct {
+	Kind       string
+	Input      string
+	Candidates []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("名称 %q 匹配到%d个%s，请改用ID指定:\n%s",
+		e.Input, len(e.Candidates), kindNames[e.Kind], formatCandidates(e.Candidates))
+}
+
+// NotFoundError 表示没有找到与名称匹配的对象
+type NotFoundError struct {
+	Kind       string
+	Input      string
+	Candidates []Candidate
+}
+
+// maxListedCandidates 未找到时最多列出的候选数量
+const maxListedCandidates = 20
+
+func (e *NotFoundError) Error() string {
+	kind := kindNames[e.Kind]
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("未找到名称或ID为 %q 的%s，当前没有可用的%s", e.Input, kind, kind)
+	}
+	candidates := e.Candidates
+	more := ""
+	if len(candidates) > maxListedCandidates {
+		more = fmt.Sprintf("\n...等共%d个", len(candidates))
+		candidates = candidates[:maxListedCandidates]
+	}
+	return fmt.Sprintf("未找到名称或ID为 %q 的%s，可选的%s:\n%s%s", e.Input, kind, kind, formatCandidates(candidates), more)
+}
+
+func formatCandidates(candidates []Candidate) string {
+	lines := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		lines = append(lines, "- "+c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Resolver 把团队、应用、组件的名称解析为API需要的标识。
+// 列表结果按客户端（即访问令牌）和路径缓存，名称未命中缓存时会重新拉取一次，以便识别刚创建的对象。
+type Resolver struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	client *api.Client
+	path   string
+}
+
+type cacheEntry struct {
+	items   interface{}
+	expires time.Time
+}
+
+// NewResolver 创建名称解析器，ttl为列表结果的缓存时间
+func NewResolver(ttl time.Duration) *Resolver {
+	return &Resolver{ttl: ttl, cache: make(map[cacheKey]cacheEntry)}
+}
+
+// names 工具框架使用的名称解析器
+var names = NewResolver(DefaultResolveTTL)
+
+// Candidates 使用工具框架的名称解析器列出候选对象，与工具共享列表缓存
+func Candidates(ctx context.Context, client *api.Client, kind, team, region, app string) ([]Candidate, error) {
+	return names.Candidates(ctx, client, kind, team, region, app)
+}
+
+// ComponentID 使用工具框架的名称解析器把组件名称解析为组件ID，用于无法使用resolve标签的列表参数
+func ComponentID(ctx context.Context, client *api.Client, team, appID, input string) (string, error) {
+	return names.Component(ctx, client, team, appID, input)
+}
+
+// AppID 使用工具框架的名称解析器在团队的全部集群中把应用名称解析为应用ID，用于同一请求中的第二个应用参数
+func AppID(ctx context.Context, client *api.Client, team, input string) (string, error) {
+	return names.App(ctx, client, team, "", input)
+}
+
+// Resolve 解析参数结构体中带resolve标签的字段，把名称替换为ID。
+// 解析顺序为团队、应用、组件，后者使用前者的解析结果；字段为空时跳过。
+func (r *Resolver) Resolve(ctx context.Context, client *api.Client, req interface{}) error {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	rules, err := rulesFor(v.Type())
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]reflect.Value)
+	for _, rule := range rules {
+		if rule.resolve != "" {
+			fields[rule.resolve] = v.Field(rule.index)
+		}
+	}
+	value := func(kind string) string {
+		if f, ok := fields[kind]; ok {
+			return strings.TrimSpace(f.String())
+		}
+		return ""
+	}
+
+	team := value(ResolveTeam)
+	if team != "" {
+		if team, err = r.Team(ctx, client, team); err != nil {
+			return err
+		}
+		fields[ResolveTeam].SetString(team)
+	}
+	app := value(ResolveApp)
+	if app != "" {
+		if team == "" {
+			return fmt.Errorf("按名称查找应用需要提供团队")
+		}
+		if app, err = r.App(ctx, client, team, value(ResolveRegion), app); err != nil {
+			return err
+		}
+		fields[ResolveApp].SetString(app)
+	}
+	if component := value(ResolveComponent); component != "" {
+		if team == "" || app == "" {
+			return fmt.Errorf("按名称查找组件需要提供团队和应用")
+		}
+		if component, err = r.Component(ctx, client, team, app, component); err != nil {
+			return err
+		}
+		fields[ResolveComponent].SetString(component)
+	}
+	return nil
+}
+
+// Team 按团队别名或团队名称查找团队，返回团队别名
+func (r *Resolver) Team(ctx context.Context, client *api.Client, input string) (string, error) {
+	return r.pick(ctx, ResolveTeam, input, func(fresh bool) ([]Candidate, bool, error) {
+		return r.teamCandidates(ctx, client, fresh)
+	})
+}
+
+// App 按应用ID或应用名称查找应用，返回应用ID。
+// region为空时在团队开通的所有集群中查找。
+func (r *Resolver) App(ctx context.Context, client *api.Client, team, region, input string) (string, error) {
+	if appIDPattern.MatchString(input) {
+		return input, nil
+	}
+	return r.pick(ctx, ResolveApp, input, func(fresh bool) ([]Candidate, bool, error) {
+		return r.appCandidates(ctx, client, team, region, fresh)
+	})
+}
+
+// Component 按组件ID、组件名称或组件英文名称查找组件，返回组件ID
+func (r *Resolver) Component(ctx context.Context, client *api.Client, team, appID, input string) (string, error) {
+	if componentIDPattern.MatchString(input) {
+		return input, nil
+	}
+	return r.pick(ctx, ResolveComponent, input, func(fresh bool) ([]Candidate, bool, error) {
+		return r.componentCandidates(ctx, client, team, appID, fresh)
+	})
+}
+
+// Candidates 列出令牌可见的某类对象，用于参数补全。team、region、app为所属范围，
+// 可以填写名称；应用需要团队，组件需要团队和应用，集群在没有团队时列出全部集群
+func (r *Resolver) Candidates(ctx context.Context, client *api.Client, kind, team, region, app string) ([]Candidate, error) {
+	var err error
+	if team != "" {
+		if team, err = r.Team(ctx, client, team); err != nil {
+			return nil, err
+		}
+	}
+	var candidates []Candidate
+	switch kind {
+	case ResolveTeam:
+		candidates, _, err = r.teamCandidates(ctx, client, false)
+	case ResolveRegion:
+		candidates, err = r.regionCandidates(ctx, client, team)
+	case ResolveApp:
+		if team == "" {
+			return nil, nil
+		}
+		candidates, _, err = r.appCandidates(ctx, client, team, region, false)
+	case ResolveComponent:
+		if team == "" || app == "" {
+			return nil, nil
+		}
+		if app, err = r.App(ctx, client, team, region, app); err != nil {
+			return nil, err
+		}
+		candidates, _, err = r.componentCandidates(ctx, client, team, app, false)
+	default:
+		return nil, fmt.Errorf("不支持的对象类型: %s", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询%s列表失败: %w", kindNames[kind], err)
+	}
+	return candidates, nil
+}
+
+func (r *Resolver) teamCandidates(ctx context.Context, client *api.Client, fresh bool) ([]Candidate, bool, error) {
+	teams, cached, err := r.teams(ctx, client, fresh)
+	if err != nil {
+		return nil, false, err
+	}
+	candidates := make([]Candidate, 0, len(teams))
+	for _, team := range teams {
+		candidates = append(candidates, Candidate{
+			ID:      team.TeamAlias,
+			Name:    team.TeamAlias,
+			aliases: []string{team.TeamName},
+		})
+	}
+	return candidates, cached, nil
+}
+
+// regionCandidates team不为空时列出团队开通的集群，否则列出全部集群
+func (r *Resolver) regionCandidates(ctx context.Context, client *api.Client, team string) ([]Candidate, error) {
+	var candidates []Candidate
+	if team == "" {
+		regions, _, err := cachedList[models.RegionInfo](ctx, r, client, "/openapi/v1/mcp/regions", false)
+		if err != nil {
+			return nil, err
+		}
+		for _, region := range regions {
+			candidates = append(candidates, Candidate{ID: region.RegionName, Name: region.RegionAlias})
+		}
+		return candidates, nil
+	}
+	teams, _, err := r.teams(ctx, client, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range teams {
+		if t.TeamAlias != team {
+			continue
+		}
+		for _, info := range t.RegionList {
+			candidates = append(candidates, Candidate{ID: info.RegionName, Name: info.RegionAlias})
+		}
+	}
+	return candidates, nil
+}
+
+func (r *Resolver) appCandidates(ctx context.Context, client *api.Client, team, region string, fresh bool) ([]Candidate, bool, error) {
+	regions := []string{region}
+	allCached := true
+	if region == "" {
+		teams, cached, err := r.teams(ctx, client, fresh)
+		if err != nil {
+			return nil, false, err
+		}
+		allCached = cached
+		regions = nil
+		for _, t := range teams {
+			if t.TeamAlias != team {
+				continue
+			}
+			for _, info := range t.RegionList {
+				regions = append(regions, info.RegionName)
+			}
+		}
+	}
+
+	var candidates []Candidate
+	for _, name := range regions {
+		path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/regions/%s/apps", url.PathEscape(team), url.PathEscape(name))
+		apps, cached, err := cachedList[models.AppItem](ctx, r, client, path, fresh)
+		if err != nil {
+			return nil, false, err
+		}
+		allCached = allCached && cached
+		for _, app := range apps {
+			candidates = append(candidates, Candidate{
+				ID:    strconv.Itoa(app.GroupID),
+				Name:  app.GroupName,
+				Extra: "集群: " + name,
+			})
+		}
+	}
+	return candidates, allCached, nil
+}
+
+func (r *Resolver) componentCandidates(ctx context.Context, client *api.Client, team, appID string, fresh bool) ([]Candidate, bool, error) {
+	path := fmt.Sprintf("/openapi/v1/mcp/teams/%s/apps/%s/components", url.PathEscape(team), url.PathEscape(appID))
+	components, cached, err := cachedList[models.ComponentInfo](ctx, r, client, path, fresh)
+	if err != nil {
+		return nil, false, err
+	}
+	candidates := make([]Candidate, 0, len(components))
+	for _, c := range components {
+		candidate := Candidate{
+			ID:      c.ServiceID,
+			Name:    c.ServiceCName,
+			aliases: []string{c.K8sComponentName, c.ServiceAlias},
+		}
+		if c.K8sComponentName != "" {
+			candidate.Extra = "英文名称: " + c.K8sComponentName
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, cached, nil
+}
+
+func (r *Resolver) teams(ctx context.Context, client *api.Client, fresh bool) ([]models.Team, bool, error) {
+	return cachedList[models.Team](ctx, r, client, "/openapi/v1/mcp/teams", fresh)
+}
+
+// pick 在候选对象中查找input。先精确匹配ID和名称，再忽略大小写匹配；
+// 候选来自缓存且没有匹配时重新拉取一次
+func (r *Resolver) pick(ctx context.Context, kind, input string, list func(fresh bool) ([]Candidate, bool, error)) (string, error) {
+	candidates, cached, err := list(false)
+	if err != nil {
+		return "", fmt.Errorf("查询%s列表失败: %w", kindNames[kind], err)
+	}
+	matched := match(candidates, input)
+	if len(matched) == 0 && cached {
+		if candidates, _, err = list(true); err != nil {
+			return "", fmt.Errorf("查询%s列表失败: %w", kindNames[kind], err)
+		}
+		matched = match(candidates, input)
+	}
+
+	switch len(matched) {
+	case 0:
+		return "", &NotFoundError{Kind: kind, Input: input, Candidates: candidates}
+	case 1:
+		if matched[0].ID != input {
+			logger.Info("%s名称 %q 解析为 %s", kindNames[kind], input, matched[0].ID)
+		}
+		return matched[0].ID, nil
+	}
+	return "", &AmbiguousError{Kind: kind, Input: input, Candidates: matched}
+}
+
+// match 返回与input匹配的候选对象，精确匹配优先于忽略大小写的匹配
+func match(candidates []Candidate, input string) []Candidate {
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		strings.EqualFold,
+	} {
+		var matched []Candidate
+		for _, c := range candidates {
+			if equal(c.ID, input) {
+				// ID唯一，命中即返回
+				return []Candidate{c}
+			}
+			for _, name := range append([]string{c.Name}, c.aliases...) {
+				if name != "" && equal(name, input) {
+					matched = append(matched, c)
+					break
+				}
+			}
+		}
+		if len(matched) > 0 {
+			return matched
+		}
+	}
+	return nil
+}
+
+// listEnvelope Rainbond列表响应
+type listEnvelope[T any] struct {
+	Data struct {
+		List []T `json:"list"`
+	} `json:"data"`
+}
+
+// cachedList 获取列表接口的数据，fresh为true时跳过缓存。第二个返回值表示结果是否来自缓存
+func cachedList[T any](ctx context.Context, r *Resolver, client *api.Client, path string, fresh bool) ([]T, bool, error) {
+	key := cacheKey{client: client, path: path}
+	now := time.Now()
+	if !fresh {
+		r.mu.Lock()
+		entry, ok := r.cache[key]
+		r.mu.Unlock()
+		if ok && now.Before(entry.expires) {
+			return entry.items.([]T), true, nil
+		}
+	}
+
+	resp, err := Do[listEnvelope[T]](ctx, client, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	items := resp.Data.List
+
+	r.mu.Lock()
+	for k, e := range r.cache {
+		if now.After(e.expires) {
+			delete(r.cache, k)
+		}
+	}
+	r.cache[key] = cacheEntry{items: items, expires: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return items, false, nil
+}
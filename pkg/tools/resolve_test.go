@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rainmcp/pkg/api"
+)
+
+type testComponentRequest struct {
+	TeamAlias string `json:"team_alias" resolve:"team"`
+	AppID     string `json:"app_id" resolve:"app"`
+	ServiceID string `json:"service_id" resolve:"component"`
+}
+
+// TestResolve 验证团队、应用、组件名称被解析为ID，同名对象返回候选列表
+func TestResolve(t *testing.T) {
+	calls := make(map[string]int)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/openapi/v1/mcp/teams":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"team_alias":"开发","team_name":"dev","region_list":[{"region_name":"r1"},{"region_name":"r2"}]}]}}`))
+		case "/openapi/v1/mcp/teams/开发/regions/r1/apps":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"group_id":7,"group_name":"shop"},{"group_id":8,"group_name":"blog"}]}}`))
+		case "/openapi/v1/mcp/teams/开发/regions/r2/apps":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"group_id":9,"group_name":"Blog"}]}}`))
+		case "/openapi/v1/mcp/teams/开发/apps/7/components":
+			w.Write([]byte(`{"code":200,"data":{"list":[{"service_id":"a1","service_cname":"前端","k8s_component_name":"web"}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer stub.Close()
+	client := api.NewClient(stub.URL, "token")
+	resolver := NewResolver(DefaultResolveTTL)
+
+	req := &testComponentRequest{TeamAlias: "DEV", AppID: "shop", ServiceID: "web"}
+	if err := resolver.Resolve(context.Background(), client, req); err != nil {
+		t.Fatalf("不应返回错误: %v", err)
+	}
+	if req.TeamAlias != "开发" || req.AppID != "7" || req.ServiceID != "a1" {
+		t.Fatalf("解析结果不正确: %+v", req)
+	}
+	if calls["/openapi/v1/mcp/teams"] != 1 {
+		t.Fatalf("团队列表应被缓存，实际请求%d次", calls["/openapi/v1/mcp/teams"])
+	}
+
+	// 精确匹配优先，blog只匹配r1中的应用
+	if id, err := resolver.App(context.Background(), client, "开发", "", "blog"); err != nil || id != "8" {
+		t.Fatalf("精确匹配失败: %v %v", id, err)
+	}
+	_, err := resolver.App(context.Background(), client, "开发", "", "BLOG")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 || !strings.Contains(err.Error(), "集群: r2") {
+		t.Fatalf("应返回同名候选列表，实际为 %v", err)
+	}
+
+	_, err = resolver.Component(context.Background(), client, "开发", "7", "api")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || !strings.Contains(err.Error(), "前端 (ID: a1") {
+		t.Fatalf("应返回未找到及可选组件，实际为 %v", err)
+	}
+}
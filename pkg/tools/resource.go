@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// URIScheme Rainbond资源URI的协议名
+const URIScheme = "rainbond://"
+
+// templateParams URI模板变量与参数字段json名称不一致时的对应关系，其余变量与字段同名
+var templateParams = map[string]string{
+	"team":   "team_alias",
+	"region": "region_name",
+}
+
+// Resource 描述以MCP资源形式暴露的Rainbond对象，读取资源等价于调用对应Spec的工具
+type Resource struct {
+	// URI 固定资源的URI，或者包含 {变量} 的资源模板，例如 rainbond://teams/{team}/apps/{app_id}
+	URI string
+	// Name 资源名称
+	Name string
+	// Description 资源描述
+	Description string
+}
+
+// isTemplate 判断URI是否为资源模板
+func (r Resource) isTemplate() bool {
+	return strings.Contains(r.URI, "{")
+}
+
+// ResourceMiddleware 包装资源读取函数，用法与工具中间件相同
+type ResourceMiddleware func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc
+
+// RegisterResource 把Spec注册为MCP资源或资源模板。资源内容与工具输出一致，
+// 模板变量按名称填入参数结构体，同样经过校验和名称解析
+func RegisterResource[Req, Resp any](mcpServer *server.Server, clients *api.Pool, resource Resource, spec Spec[Req, Resp], middlewares ...ResourceMiddleware) {
+	handler := NewResourceHandler(clients, spec)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	if !resource.isTemplate() {
+		mcpServer.RegisterResource(&protocol.Resource{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MimeType:    "application/json",
+		}, handler)
+		return
+	}
+
+	template := &protocol.ResourceTemplate{
+		URITemplate: resource.URI,
+		Name:        resource.Name,
+		Description: resource.Description,
+		MimeType:    "application/json",
+	}
+	if err := mcpServer.RegisterResourceTemplate(template, handler); err != nil {
+		logger.Error("注册资源模板 %s 失败: %v", resource.URI, err)
+	}
+}
+
+// NewResourceHandler 根据Spec生成资源读取函数
+func NewResourceHandler[Req, Resp any](clients *api.Pool, spec Spec[Req, Resp]) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+		logger.Info("读取资源: %s", request.URI)
+		text, err := spec.invoke(ctx, clients, templateArgs(request.Arguments))
+		if err != nil {
+			return nil, err
+		}
+		return protocol.NewReadResourceResult([]protocol.ResourceContents{
+			protocol.TextResourceContents{
+				URI:      request.URI,
+				Text:     text,
+				MimeType: mimeType(text),
+			},
+		}), nil
+	}
+}
+
+// templateArgs 把URI模板变量转换为工具参数
+func templateArgs(vars map[string]interface{}) json.RawMessage {
+	args := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		if field, ok := templateParams[name]; ok {
+			name = field
+		}
+		// 模板变量的值为字符串列表，简单变量只有一个元素
+		if values, ok := value.([]string); ok && len(values) == 1 {
+			value = values[0]
+		}
+		args[name] = value
+	}
+	// 值只有字符串和字符串列表，序列化不会失败
+	raw, _ := json.Marshal(args)
+	return raw
+}
+
+// mimeType 按内容判断资源类型，Render返回的纯文本不是JSON
+func mimeType(text string) string {
+	if json.Valid([]byte(text)) {
+		return "application/json"
+	}
+	return "text/plain"
+}
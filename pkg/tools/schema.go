@@ -0,0 +1,295 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 参数结构体支持的标签：
+//   - json:        字段名，没有omitempty时字段必填
+//   - required:    "true"/"false"，覆盖json标签推断的必填规则
+//   - description: 字段说明
+//   - enum:        逗号分隔的可选值
+//   - min/max:     数值的取值范围，字符串的长度范围，数组的元素个数范围
+//   - pattern:     字符串需要匹配的正则表达式
+//   - default:     未传入时使用的默认值，设置默认值的字段不再必填
+//   - resolve:     team/region/app/component，字段可以填写名称，调用前由Resolver解析为ID
+
+// fieldRule 描述参数结构体中一个字段的约束
+type fieldRule struct {
+	name        string
+	index       int
+	kind        reflect.Kind
+	typ         reflect.Type
+	required    bool
+	description string
+	enum        []string
+	min, max    *float64
+	pattern     *regexp.Regexp
+	def         interface{}
+	// resolve 需要按名称解析的对象类型
+	resolve string
+	// nested 字段为结构体或结构体切片时的子字段约束
+	nested []fieldRule
+}
+
+var rulesCache sync.Map
+
+// rulesFor 解析结构体类型的字段约束，结果按类型缓存
+func rulesFor(t reflect.Type) ([]fieldRule, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("参数类型必须是结构体: %v", t)
+	}
+	if cached, ok := rulesCache.Load(t); ok {
+		return cached.([]fieldRule), nil
+	}
+
+	var rules []fieldRule
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous {
+			return nil, fmt.Errorf("参数结构体 %v 不支持嵌入字段 %s", t, field.Name)
+		}
+		rule, err := parseRule(field)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s.%s: %v", t.Name(), field.Name, err)
+		}
+		rule.index = i
+		rules = append(rules, rule)
+	}
+	rulesCache.Store(t, rules)
+	return rules, nil
+}
+
+// parseRule 根据结构体标签解析字段约束
+func parseRule(field reflect.StructField) (fieldRule, error) {
+	typ := field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	rule := fieldRule{
+		kind:        typ.Kind(),
+		typ:         typ,
+		required:    true,
+		description: field.Tag.Get("description"),
+	}
+
+	parts := strings.Split(field.Tag.Get("json"), ",")
+	rule.name = parts[0]
+	if rule.name == "" {
+		rule.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			rule.required = false
+		}
+	}
+
+	if v := field.Tag.Get("enum"); v != "" {
+		for _, option := range strings.Split(v, ",") {
+			rule.enum = append(rule.enum, strings.TrimSpace(option))
+		}
+	}
+	for tag, target := range map[string]**float64{"min": &rule.min, "max": &rule.max} {
+		if v := field.Tag.Get(tag); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return rule, fmt.Errorf("%s标签不是数字: %q", tag, v)
+			}
+			*target = &n
+		}
+	}
+	if v := field.Tag.Get("pattern"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return rule, fmt.Errorf("pattern标签不是合法的正则表达式: %v", err)
+		}
+		rule.pattern = re
+	}
+	if v, ok := field.Tag.Lookup("default"); ok {
+		def, err := parseDefault(typ, v)
+		if err != nil {
+			return rule, err
+		}
+		rule.def = def
+		rule.required = false
+	}
+	if v := field.Tag.Get("resolve"); v != "" {
+		if _, ok := kindNames[v]; !ok && v != ResolveRegion {
+			return rule, fmt.Errorf("resolve标签不合法: %q", v)
+		}
+		if typ.Kind() != reflect.String {
+			return rule, fmt.Errorf("resolve标签只能用于字符串字段")
+		}
+		rule.resolve = v
+		rule.description += resolveHints[v]
+	}
+	if v := field.Tag.Get("required"); v != "" {
+		required, err := strconv.ParseBool(v)
+		if err != nil {
+			return rule, fmt.Errorf("required标签不是布尔值: %q", v)
+		}
+		rule.required = required
+	}
+
+	elem := typ
+	if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		elem = typ.Elem()
+	}
+	if elem.Kind() == reflect.Struct {
+		nested, err := rulesFor(elem)
+		if err != nil {
+			return rule, err
+		}
+		rule.nested = nested
+	}
+	return rule, nil
+}
+
+// parseDefault 把default标签转换为与字段类型一致的JSON值
+func parseDefault(typ reflect.Type, value string) (interface{}, error) {
+	switch typ.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("default标签不是布尔值: %q", value)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("default标签不是数字: %q", value)
+		}
+		return n, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, fmt.Errorf("default标签不是合法的JSON: %q", value)
+	}
+	return v, nil
+}
+
+// jsonType 返回Go类型对应的JSON Schema类型
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return ""
+}
+
+// InputSchema 生成参数结构体的JSON Schema，包含必填、枚举、范围、正则和默认值约束
+func InputSchema(v interface{}) (json.RawMessage, error) {
+	rules, err := rulesFor(reflect.TypeOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(objectSchema(rules))
+}
+
+func objectSchema(rules []fieldRule) map[string]interface{} {
+	properties := make(map[string]interface{}, len(rules))
+	required := make([]string, 0)
+	for _, rule := range rules {
+		properties[rule.name] = propertySchema(rule)
+		if rule.required {
+			required = append(required, rule.name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func propertySchema(rule fieldRule) map[string]interface{} {
+	property := make(map[string]interface{})
+	if t := jsonType(rule.kind); t != "" {
+		property["type"] = t
+	}
+	if rule.description != "" {
+		property["description"] = rule.description
+	}
+	if len(rule.enum) > 0 {
+		property["enum"] = enumValues(rule)
+	}
+	if rule.def != nil {
+		property["default"] = rule.def
+	}
+
+	minKey, maxKey := "minimum", "maximum"
+	switch rule.kind {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		minKey, maxKey = "minItems", "maxItems"
+	}
+	if rule.min != nil {
+		property[minKey] = *rule.min
+	}
+	if rule.max != nil {
+		property[maxKey] = *rule.max
+	}
+	if rule.pattern != nil {
+		property["pattern"] = rule.pattern.String()
+	}
+
+	switch {
+	case rule.kind == reflect.Struct:
+		for k, v := range objectSchema(rule.nested) {
+			property[k] = v
+		}
+	case rule.kind == reflect.Slice || rule.kind == reflect.Array:
+		if rule.nested != nil {
+			property["items"] = objectSchema(rule.nested)
+		} else if t := jsonType(rule.typ.Elem().Kind()); t != "" {
+			property["items"] = map[string]interface{}{"type": t}
+		}
+	}
+	return property
+}
+
+// enumValues 按字段类型输出枚举值，数值字段输出数字
+func enumValues(rule fieldRule) []interface{} {
+	values := make([]interface{}, 0, len(rule.enum))
+	for _, option := range rule.enum {
+		if t := jsonType(rule.kind); t == "integer" || t == "number" {
+			if n, err := strconv.ParseFloat(option, 64); err == nil {
+				values = append(values, n)
+				continue
+			}
+		}
+		values = append(values, option)
+	}
+	return values
+}
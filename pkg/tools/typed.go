@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+	"rainmcp/pkg/utils"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+// Spec 描述一个转发到Rainbond API的类型化工具，Req为工具参数，Resp为Rainbond响应结构
+type Spec[Req, Resp any] struct {
+	// Name 工具名称
+	Name string
+	// Description 工具描述
+	Description string
+	// Action 错误提示中的操作描述，例如"获取应用列表"
+	Action string
+	// Method HTTP方法，默认GET
+	Method string
+	// Path 根据参数生成API路径
+	Path func(req *Req) string
+	// Body 根据参数生成请求体，为空时POST/PUT直接发送参数本身
+	Body func(req *Req) interface{}
+	// Validate 结构体标签无法表达的额外校验
+	Validate func(req *Req) error
+	// Call 需要多次请求或自定义流程时替代Method/Path/Body
+	Call func(ctx context.Context, client *api.Client, req *Req) (*Resp, error)
+	// Destructive 工具会删除数据或造成不可恢复的变更，通过destructiveHint提示客户端在调用前向用户确认
+	Destructive bool
+	// Render 把响应转换为输出内容，返回字符串时原样输出，其他值格式化为JSON；为空时输出带字段描述的响应
+	Render func(req *Req, resp *Resp) (interface{}, error)
+}
+
+// RegisterTyped 根据Spec生成工具定义和处理函数并注册到MCP服务器，参数结构体的标签约束写入inputSchema
+func RegisterTyped[Req, Resp any](mcpServer *server.Server, clients *api.Pool, spec Spec[Req, Resp], middlewares ...server.ToolMiddleware) {
+	var req Req
+	schema, err := InputSchema(req)
+	if err != nil {
+		logger.Error("创建工具 %s 失败: %v", spec.Name, err)
+		return
+	}
+	tool := protocol.NewToolWithRawSchema(spec.Name, spec.Description, schema)
+	if spec.Destructive {
+		readOnly, destructive := false, true
+		tool.Annotations = &protocol.ToolAnnotations{ReadOnlyHint: &readOnly, DestructiveHint: &destructive}
+	}
+	mcpServer.RegisterTool(tool, withProgress(mcpServer, NewHandler(clients, spec)), middlewares...)
+}
+
+// NewHandler 根据Spec生成工具处理函数：解码并校验参数、把名称解析为ID、调用Rainbond API、解析响应并渲染输出
+func NewHandler[Req, Resp any](clients *api.Pool, spec Spec[Req, Resp]) server.ToolHandlerFunc {
+	return func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		text, err := spec.invoke(ctx, clients, request.RawArguments)
+		if err != nil {
+			return utils.ErrorTextResult(err.Error()), nil
+		}
+		return utils.TextResult(text), nil
+	}
+}
+
+// invoke 执行一次调用并返回输出文本。返回的错误信息已经整理为面向模型的描述，可以直接展示
+func (spec Spec[Req, Resp]) invoke(ctx context.Context, clients *api.Pool, raw json.RawMessage) (string, error) {
+	client, err := clients.FromContext(ctx)
+	if err != nil {
+		logger.Error("获取API客户端失败: %v", err)
+		return "", errors.New(utils.ErrorMessage("获取API客户端", err))
+	}
+
+	req, resp, err := spec.execute(ctx, client, raw)
+	if err != nil {
+		var rawErr *RawResponseError
+		if errors.As(err, &rawErr) {
+			// 响应不符合预期结构时输出原始数据，避免丢失信息；其中的敏感环境变量与正常输出一样打码
+			logger.Warn("解析%s响应失败: %v", spec.Action, rawErr.Err)
+			return utils.FormatJSON(maskRawBody(rawErr.Body)), nil
+		}
+		return "", err
+	}
+
+	var output interface{} = resp
+	if spec.Render != nil {
+		if output, err = spec.Render(req, resp); err != nil {
+			logger.Error("渲染%s结果失败: %v", spec.Action, err)
+			return "", errors.New(utils.ErrorMessage(spec.Action, err))
+		}
+	}
+	return renderOutput(output), nil
+}
+
+// execute 解码并校验参数、把名称解析为ID、调用Rainbond API
+func (spec Spec[Req, Resp]) execute(ctx context.Context, client *api.Client, raw json.RawMessage) (*Req, *Resp, error) {
+	req := new(Req)
+	if err := Decode(raw, req); err != nil {
+		logger.Error("工具 %s 参数校验失败: %v", spec.Name, err)
+		return nil, nil, fmt.Errorf("参数校验失败: %v", err)
+	}
+	if spec.Validate != nil {
+		if err := spec.Validate(req); err != nil {
+			logger.Error("工具 %s 参数校验失败: %v", spec.Name, err)
+			return nil, nil, fmt.Errorf("参数校验失败: %v", err)
+		}
+	}
+	if err := names.Resolve(ctx, client, req); err != nil {
+		logger.Error("工具 %s 解析名称失败: %v", spec.Name, err)
+		return nil, nil, errors.New(utils.ErrorMessage("解析名称", err))
+	}
+
+	var (
+		resp *Resp
+		err  error
+	)
+	if spec.Call != nil {
+		resp, err = spec.Call(ctx, client, req)
+	} else {
+		resp, err = spec.do(ctx, client, req)
+	}
+	if err != nil {
+		var rawErr *RawResponseError
+		if errors.As(err, &rawErr) {
+			return req, nil, err
+		}
+		logger.Error("%s失败: %v", spec.Action, err)
+		return req, nil, errors.New(utils.ErrorMessage(spec.Action, err))
+	}
+	return req, resp, nil
+}
+
+// Fetch 按资源模板变量调用Spec并返回响应，供资源订阅轮询等需要原始响应的场景使用
+func (spec Spec[Req, Resp]) Fetch(ctx context.Context, client *api.Client, args map[string]string) (*Resp, error) {
+	values := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		values[name] = value
+	}
+	_, resp, err := spec.execute(ctx, client, templateArgs(values))
+	return resp, err
+}
+
+// do 按Method/Path/Body发送请求
+func (spec Spec[Req, Resp]) do(ctx context.Context, client *api.Client, req *Req) (*Resp, error) {
+	method := spec.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	path := spec.Path(req)
+	logger.Info("%s: %s %s", spec.Action, method, path)
+
+	var body interface{}
+	if method == http.MethodPost || method == http.MethodPut {
+		body = req
+		if spec.Body != nil {
+			body = spec.Body(req)
+		}
+	}
+	return Do[Resp](ctx, client, method, path, body)
+}
+
+// Do 发送请求并把响应解析为Resp。响应体中的业务错误码转换为APIError，
+// 响应无法解析为Resp时返回RawResponseError，其中保留原始响应体
+func Do[Resp any](ctx context.Context, client *api.Client, method, path string, body interface{}) (*Resp, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch method {
+	case http.MethodPost:
+		data, err = client.Post(ctx, path, body)
+	case http.MethodPut:
+		data, err = client.Put(ctx, path, body)
+	case http.MethodDelete:
+		data, err = client.Delete(ctx, path)
+	default:
+		data, err = client.Get(ctx, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := api.CheckEnvelope(data); err != nil {
+		return nil, err
+	}
+
+	resp := new(Resp)
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, &RawResponseError{Body: data, Err: err}
+	}
+	return resp, nil
+}
+
+// maskRawBody 把原始响应中的敏感环境变量打码，无法解析为JSON时原样返回
+func maskRawBody(body []byte) interface{} {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body
+	}
+	return utils.MaskSecretEnvs(data)
+}
+
+// RawResponseError 表示Rainbond响应无法解析为预期结构
+type RawResponseError struct {
+	Body []byte
+	Err  error
+}
+
+// Error 实现error接口
+func (e *RawResponseError) Error() string {
+	return fmt.Sprintf("解析响应失败: %v", e.Err)
+}
+
+// Unwrap 返回解析错误
+func (e *RawResponseError) Unwrap() error {
+	return e.Err
+}
+
+// renderOutput 把输出内容转换为文本，结构体附带字段描述
+func renderOutput(output interface{}) string {
+	if text, ok := output.(string); ok {
+		return text
+	}
+	resultJSON, err := utils.MarshalJSONWithDescription(output)
+	if err != nil {
+		logger.Error("带描述的格式化响应数据失败: %v", err)
+		return utils.FormatJSON(output)
+	}
+	return string(resultJSON)
+}
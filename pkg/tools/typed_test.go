@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rainmcp/pkg/api"
+)
+
+type testDetailRequest struct {
+	ServiceID string `json:"service_id"`
+}
+
+type testDetailResponse struct {
+	Data struct {
+		Bean struct {
+			Envs []struct {
+				AttrName string `json:"attr_name"`
+			} `json:"envs"`
+		} `json:"bean"`
+	} `json:"data"`
+}
+
+// TestInvokeRawResponseMasksSecrets 验证响应结构不符合预期、原样输出时敏感环境变量同样被打码
+func TestInvokeRawResponseMasksSecrets(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// envs 变成了以变量名为键的对象，无法解析为预期的数组
+		w.Write([]byte(`{"code":200,"data":{"bean":{"envs":{"db":{"attr_name":"DB_PASSWORD","attr_value":"s3cret"},"mode":{"attr_name":"MODE","attr_value":"prod"}}}}}`))
+	}))
+	defer stub.Close()
+
+	spec := Spec[testDetailRequest, testDetailResponse]{
+		Name:   "test_detail",
+		Action: "获取组件详情",
+		Path:   func(*testDetailRequest) string { return "/detail" },
+	}
+	ctx := api.ContextWithToken(context.Background(), "token")
+	text, err := spec.invoke(ctx, api.NewPool(stub.URL), []byte(`{"service_id":"a1"}`))
+	if err != nil {
+		t.Fatalf("invoke 返回错误: %v", err)
+	}
+	if strings.Contains(text, "s3cret") {
+		t.Errorf("原样输出的响应中包含敏感变量的明文: %s", text)
+	}
+	if !strings.Contains(text, "prod") {
+		t.Errorf("普通变量不应打码: %s", text)
+	}
+}
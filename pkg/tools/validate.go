@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Decode 把工具参数解码到req：先补齐默认值，再按结构体标签校验，最后反序列化。
+// 校验规则与InputSchema对外声明的JSON Schema一致，保证在请求Rainbond之前拦截非法参数。
+func Decode(raw json.RawMessage, req interface{}) error {
+	rules, err := rulesFor(reflect.TypeOf(req))
+	if err != nil {
+		return err
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("{}")
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return fmt.Errorf("参数不是合法的JSON对象: %v", err)
+	}
+
+	defaulted := applyDefaults(rules, args)
+	if coerceIDs(rules, args) || defaulted {
+		if raw, err = json.Marshal(args); err != nil {
+			return fmt.Errorf("补齐默认值失败: %v", err)
+		}
+	}
+	if err := checkObject(rules, args, ""); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("字段 %s 的类型应为 %s", typeErr.Field, typeErr.Type)
+		}
+		return fmt.Errorf("解析参数失败: %v", err)
+	}
+	return nil
+}
+
+// applyDefaults 为未传入的字段填充默认值，返回是否有改动
+func applyDefaults(rules []fieldRule, args map[string]interface{}) bool {
+	changed := false
+	for _, rule := range rules {
+		value, present := args[rule.name]
+		if (!present || value == nil) && rule.def != nil {
+			args[rule.name] = rule.def
+			changed = true
+			continue
+		}
+		if rule.nested == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			changed = applyDefaults(rule.nested, v) || changed
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					changed = applyDefaults(rule.nested, m) || changed
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// coerceIDs 把传给可解析字段的整数转换为字符串。
+// 应用列表返回的group_id是数字，而参数中的app_id是字符串，模型经常直接照搬数字
+func coerceIDs(rules []fieldRule, args map[string]interface{}) bool {
+	changed := false
+	for _, rule := range rules {
+		if rule.resolve == "" {
+			continue
+		}
+		if n, ok := args[rule.name].(float64); ok && n == float64(int64(n)) {
+			args[rule.name] = strconv.FormatInt(int64(n), 10)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// checkObject 按字段约束校验参数对象，prefix为嵌套字段的路径前缀
+func checkObject(rules []fieldRule, args map[string]interface{}, prefix string) error {
+	var missing []string
+	var problems []string
+	for _, rule := range rules {
+		name := prefix + rule.name
+		value, present := args[rule.name]
+		if !present || value == nil || isBlankString(value) {
+			if rule.required {
+				missing = append(missing, name)
+			}
+			continue
+		}
+		if err := checkValue(rule, value, name); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(missing) > 0 {
+		problems = append([]string{fmt.Sprintf("缺少必填字段: %s", strings.Join(missing, ", "))}, problems...)
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkValue 校验单个字段的类型、枚举、范围和正则约束
+func checkValue(rule fieldRule, value interface{}, name string) error {
+	switch jsonType(rule.kind) {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("字段 %s 的类型应为字符串", name)
+		}
+		if err := checkEnum(rule, s, name); err != nil {
+			return err
+		}
+		if err := checkRange(rule, float64(utf8.RuneCountInString(s)), name, "长度"); err != nil {
+			return err
+		}
+		if rule.pattern != nil && !rule.pattern.MatchString(s) {
+			return fmt.Errorf("字段 %s 的取值 %q 格式不正确，应匹配 %s", name, s, rule.pattern.String())
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("字段 %s 的类型应为数字", name)
+		}
+		if jsonType(rule.kind) == "integer" && n != float64(int64(n)) {
+			return fmt.Errorf("字段 %s 应为整数", name)
+		}
+		if err := checkEnum(rule, strconv.FormatFloat(n, 'f', -1, 64), name); err != nil {
+			return err
+		}
+		if err := checkRange(rule, n, name, "取值"); err != nil {
+			return err
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("字段 %s 的类型应为布尔值", name)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("字段 %s 的类型应为数组", name)
+		}
+		if err := checkRange(rule, float64(len(items)), name, "元素个数"); err != nil {
+			return err
+		}
+		if rule.nested != nil {
+			for i, item := range items {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("字段 %s[%d] 的类型应为对象", name, i)
+				}
+				if err := checkObject(rule.nested, m, fmt.Sprintf("%s[%d].", name, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("字段 %s 的类型应为对象", name)
+		}
+		if rule.nested != nil {
+			return checkObject(rule.nested, m, name+".")
+		}
+	}
+	return nil
+}
+
+// checkEnum 校验取值是否在enum标签列出的范围内
+func checkEnum(rule fieldRule, actual string, name string) error {
+	if len(rule.enum) == 0 {
+		return nil
+	}
+	for _, option := range rule.enum {
+		if actual == option {
+			return nil
+		}
+	}
+	return fmt.Errorf("字段 %s 的取值 %q 不合法，可选值: %s", name, actual, strings.Join(rule.enum, "/"))
+}
+
+// checkRange 校验min/max约束，what描述被比较的量，例如"取值"、"长度"
+func checkRange(rule fieldRule, n float64, name, what string) error {
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	if rule.min != nil && n < *rule.min {
+		return fmt.Errorf("字段 %s 的%s不能小于 %s，当前为 %s", name, what, format(*rule.min), format(n))
+	}
+	if rule.max != nil && n > *rule.max {
+		return fmt.Errorf("字段 %s 的%s不能大于 %s，当前为 %s", name, what, format(*rule.max), format(n))
+	}
+	return nil
+}
+
+func isBlankString(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testPortRequest struct {
+	TeamAlias string   `json:"team_alias" description:"团队别名"`
+	Port      int      `json:"port" min:"1" max:"65535"`
+	Protocol  string   `json:"protocol" enum:"tcp,udp,http"`
+	Outer     bool     `json:"is_outer_service" default:"false"`
+	Branch    string   `json:"branch" default:"master" pattern:"^[a-z0-9._/-]+$"`
+	Alias     string   `json:"alias,omitempty" max:"8"`
+	Tags      []string `json:"tags,omitempty" max:"2"`
+}
+
+// TestDecode 验证必填、枚举、范围、正则和默认值约束在解码时生效
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    string
+		wantErr string
+	}{
+		{name: "合法参数", args: `{"team_alias":"dev","port":80,"protocol":"http"}`},
+		{name: "缺少必填字段", args: `{"port":80}`, wantErr: "缺少必填字段: team_alias, protocol"},
+		{name: "空字符串视为缺少", args: `{"team_alias":" ","port":80,"protocol":"tcp"}`, wantErr: "缺少必填字段: team_alias"},
+		{name: "枚举", args: `{"team_alias":"dev","port":80,"protocol":"https"}`, wantErr: `字段 protocol 的取值 "https" 不合法`},
+		{name: "最大值", args: `{"team_alias":"dev","port":99999,"protocol":"tcp"}`, wantErr: "字段 port 的取值不能大于 65535"},
+		{name: "最小值", args: `{"team_alias":"dev","port":0,"protocol":"tcp"}`, wantErr: "字段 port 的取值不能小于 1"},
+		{name: "整数", args: `{"team_alias":"dev","port":80.5,"protocol":"tcp"}`, wantErr: "字段 port 应为整数"},
+		{name: "正则", args: `{"team_alias":"dev","port":80,"protocol":"tcp","branch":"Main Branch"}`, wantErr: "字段 branch 的取值"},
+		{name: "字符串长度", args: `{"team_alias":"dev","port":80,"protocol":"tcp","alias":"too-long-alias"}`, wantErr: "字段 alias 的长度不能大于 8"},
+		{name: "数组长度", args: `{"team_alias":"dev","port":80,"protocol":"tcp","tags":["a","b","c"]}`, wantErr: "字段 tags 的元素个数不能大于 2"},
+		{name: "类型错误", args: `{"team_alias":"dev","port":"80","protocol":"tcp"}`, wantErr: "字段 port 的类型应为数字"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := new(testPortRequest)
+			err := Decode(json.RawMessage(c.args), req)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatalf("不应返回错误: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("错误应包含 %q，实际为 %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestDecodeDefaults 验证未传入的字段使用默认值
+func TestDecodeDefaults(t *testing.T) {
+	req := new(testPortRequest)
+	req.Outer = true
+	if err := Decode(json.RawMessage(`{"team_alias":"dev","port":80,"protocol":"tcp"}`), req); err != nil {
+		t.Fatalf("不应返回错误: %v", err)
+	}
+	if req.Branch != "master" || req.Outer {
+		t.Fatalf("默认值未生效: branch=%q is_outer_service=%v", req.Branch, req.Outer)
+	}
+}
+
+// TestInputSchema 验证标签约束写入JSON Schema
+func TestInputSchema(t *testing.T) {
+	raw, err := InputSchema(testPortRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(schema.Required, ",") != "team_alias,port,protocol" {
+		t.Fatalf("必填字段不正确: %v", schema.Required)
+	}
+	port := schema.Properties["port"]
+	if port["type"] != "integer" || port["minimum"] != 1.0 || port["maximum"] != 65535.0 {
+		t.Fatalf("port约束不正确: %v", port)
+	}
+	if enum, _ := schema.Properties["protocol"]["enum"].([]interface{}); len(enum) != 3 {
+		t.Fatalf("protocol枚举不正确: %v", schema.Properties["protocol"])
+	}
+	branch := schema.Properties["branch"]
+	if branch["default"] != "master" || branch["pattern"] == nil {
+		t.Fatalf("branch约束不正确: %v", branch)
+	}
+	if schema.Properties["alias"]["maxLength"] != 8.0 || schema.Properties["tags"]["maxItems"] != 2.0 {
+		t.Fatalf("长度约束不正确: %v %v", schema.Properties["alias"], schema.Properties["tags"])
+	}
+}
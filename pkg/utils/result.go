@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"rainmcp/pkg/api"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// TextResult 返回只包含一段文本的工具结果
+func TextResult(text string) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+// ErrorTextResult 返回只包含一段错误文本的工具错误结果
+func ErrorTextResult(text string) *protocol.CallToolResult {
+	result := TextResult(text)
+	result.IsError = true
+	return result
+}
+
+// ErrorResult 把错误转换为工具错误结果，action描述失败的操作，例如"获取团队列表"。
+// Rainbond API错误使用msg_show作为错误信息并附带处理建议，便于模型决定下一步操作。
+func ErrorResult(action string, err error) *protocol.CallToolResult {
+	return ErrorTextResult(ErrorMessage(action, err))
+}
+
+// ErrorMessage 生成与ErrorResult相同的错误描述，用于资源读取等不返回工具结果的场景
+func ErrorMessage(action string, err error) string {
+	return fmt.Sprintf("%s失败: %s", action, describeError(err))
+}
+
+// describeError 生成面向模型的错误描述
+func describeError(err error) string {
+	var apiErr *api.APIError
+	var openErr *api.CircuitOpenError
+	switch {
+	case errors.As(err, &openErr):
+		return fmt.Sprintf("%v\n错误类型: %s\n建议: Rainbond控制台连续请求失败，可能正在重启或维护，请等待其恢复后再重试，不要反复调用", err, api.ErrorKindServer)
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("%s\n错误类型: %s (HTTP %d)\n建议: %s", apiErr.Message(), apiErr.Kind(), apiErr.StatusCode, apiErr.Hint())
+	case errors.Is(err, api.ErrInvalidToken):
+		return fmt.Sprintf("%v\n错误类型: %s\n建议: 请检查Rainbond访问令牌", err, api.ErrorKindAuth)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("%v\n建议: 请求超时，请稍后重试或调大工具超时时间", err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Sprintf("%v\n建议: 请求已被取消，如仍需要结果请重新调用", err)
+	}
+	return err.Error()
+}
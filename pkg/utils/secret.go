@@ -0,0 +1,55 @@
+package utils
+
+import "strings"
+
+// MaskedValue 敏感信息打码后的取值，不保留原值的长度
+const MaskedValue = "******"
+
+// secretWords 变量名包含这些词时视为敏感信息
+var secretWords = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "CREDENTIAL", "APIKEY"}
+
+// secretSegments 较短的关键词只在作为变量名中的完整片段时匹配，避免 KEYCLOAK_URL、MONKEY_MODE 之类的误判；
+// 只有一个片段的变量名(如 shell 的 PWD)不按片段匹配
+var secretSegments = map[string]struct{}{"PWD": {}, "PASS": {}, "KEY": {}}
+
+// IsSecretName 判断变量名是否看起来是密码、令牌、密钥等敏感信息。片段按 _ - . 分隔，不区分大小写
+func IsSecretName(name string) bool {
+	name = strings.ToUpper(name)
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	segments := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	if len(segments) < 2 {
+		return false
+	}
+	for _, segment := range segments {
+		if _, ok := secretSegments[segment]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecretEnvs 在任意JSON数据中查找环境变量结构(同时包含attr_name和attr_value的对象)，
+// 把敏感变量的取值打码，用于无法按预期结构解析、需要原样输出的响应
+func MaskSecretEnvs(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = MaskSecretEnvs(value)
+		}
+		name, hasName := v["attr_name"].(string)
+		value, hasValue := v["attr_value"].(string)
+		secret, _ := v["is_secret"].(bool)
+		if hasName && hasValue && value != "" && (secret || IsSecretName(name)) {
+			v["attr_value"] = MaskedValue
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = MaskSecretEnvs(item)
+		}
+	}
+	return data
+}
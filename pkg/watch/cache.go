@@ -0,0 +1,53 @@
+package watch
+
+import (
+	"context"
+	"sync"
+
+	"rainmcp/pkg/api"
+)
+
+// tickCacheKey 在上下文中保存一次轮询内共享的查询结果
+type tickCacheKey struct{}
+
+// tickCache 一次轮询内的查询结果，同一令牌同时订阅了应用和其中的组件时，组件状态只查询一次
+type tickCache struct {
+	mu      sync.Mutex
+	entries map[tickCacheEntryKey]*tickCacheEntry
+}
+
+type tickCacheEntryKey struct {
+	token string
+	key   string
+}
+
+type tickCacheEntry struct {
+	once   sync.Once
+	digest string
+	err    error
+}
+
+// withTickCache 为一次轮询创建查询结果缓存，轮询结束后随上下文丢弃
+func withTickCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tickCacheKey{}, &tickCache{entries: make(map[tickCacheEntryKey]*tickCacheEntry)})
+}
+
+// Cached 在同一次轮询内复用相同令牌和key的查询结果，并发调用时只查询一次。
+// 不在轮询中调用时直接查询
+func Cached(ctx context.Context, key string, fetch func() (string, error)) (string, error) {
+	cache, ok := ctx.Value(tickCacheKey{}).(*tickCache)
+	if !ok {
+		return fetch()
+	}
+	token, _ := api.TokenFromContext(ctx)
+	cache.mu.Lock()
+	entry, ok := cache.entries[tickCacheEntryKey{token: token, key: key}]
+	if !ok {
+		entry = &tickCacheEntry{}
+		cache.entries[tickCacheEntryKey{token: token, key: key}] = entry
+	}
+	cache.mu.Unlock()
+
+	entry.once.Do(func() { entry.digest, entry.err = fetch() })
+	return entry.digest, entry.err
+}
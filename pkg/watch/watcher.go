@@ -0,0 +1,310 @@
+package watch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"rainmcp/pkg/api"
+	"rainmcp/pkg/logger"
+)
+
+// DefaultInterval 轮询订阅对象状态的默认间隔
+const DefaultInterval = 10 * time.Second
+
+// probeTimeout 单次状态查询的超时时间
+const probeTimeout = 10 * time.Second
+
+// Probe 查询被订阅对象的当前状态，返回用于比较的摘要。args为URI模板变量
+type Probe func(ctx context.Context, client *api.Client, args map[string]string) (string, error)
+
+// Notifier 通知订阅了uri的会话资源已更新
+type Notifier func(ctx context.Context, uri string) error
+
+// Watcher 轮询会话订阅的资源，状态摘要变化时发送资源更新通知。
+// 订阅按会话记录，同一令牌订阅的同一资源只轮询一次。
+type Watcher struct {
+	// Interval 轮询间隔，在Run之前设置
+	Interval time.Duration
+
+	clients *api.Pool
+
+	mu        sync.Mutex
+	probes    []pattern
+	notifiers []Notifier
+	// sessions 会话ID -> 令牌和订阅的URI
+	sessions map[string]*session
+	// digests 上一次轮询得到的状态摘要
+	digests map[target]string
+}
+
+type session struct {
+	token string
+	uris  map[string]struct{}
+}
+
+// target 一个需要轮询的对象，不同令牌看到的内容可能不同，分别轮询
+type target struct {
+	token string
+	uri   string
+}
+
+type pattern struct {
+	segments []string
+	probe    Probe
+}
+
+// New 创建资源订阅轮询器，interval不大于0时使用DefaultInterval
+func New(clients *api.Pool, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Watcher{
+		Interval: interval,
+		clients:  clients,
+		sessions: make(map[string]*session),
+		digests:  make(map[target]string),
+	}
+}
+
+// Handle 为URI模板注册状态查询函数，模板格式与资源模板一致，例如 rainbond://teams/{team}/apps/{app_id}
+func (w *Watcher) Handle(uriTemplate string, probe Probe) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.probes = append(w.probes, pattern{segments: strings.Split(uriTemplate, "/"), probe: probe})
+}
+
+// AddNotifier 添加资源更新通知的发送方，每个MCP服务器一个
+func (w *Watcher) AddNotifier(notifier Notifier) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.notifiers = append(w.notifiers, notifier)
+}
+
+// Subscribe 记录会话对资源的订阅，没有对应状态查询函数的资源不轮询
+func (w *Watcher) Subscribe(sessionID, token, uri string) {
+	if _, _, ok := w.match(uri); !ok {
+		logger.Debug("资源 %s 不支持状态推送，忽略订阅", uri)
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.sessions[sessionID]
+	if !ok {
+		s = &session{token: token, uris: make(map[string]struct{})}
+		w.sessions[sessionID] = s
+	}
+	s.uris[uri] = struct{}{}
+	logger.Info("会话 %s 订阅资源 %s", sessionID, uri)
+
+	// 立即记录当前状态，订阅后第一次轮询之前发生的变化同样能被发现
+	go w.check(context.Background(), target{token: token, uri: uri})
+}
+
+// Unsubscribe 取消会话对资源的订阅
+func (w *Watcher) Unsubscribe(sessionID, uri string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.sessions[sessionID]; ok {
+		delete(s.uris, uri)
+		if len(s.uris) == 0 {
+			delete(w.sessions, sessionID)
+		}
+	}
+	w.pruneDigests()
+}
+
+// Forget 会话结束时清除其全部订阅
+func (w *Watcher) Forget(sessionID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.sessions, sessionID)
+	w.pruneDigests()
+}
+
+// Observe 检查客户端发来的JSON-RPC消息，记录其中的资源订阅和取消订阅
+func (w *Watcher) Observe(sessionID, token string, msg []byte) {
+	var request struct {
+		Method string `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(msg, &request); err != nil || request.Params.URI == "" {
+		return
+	}
+	switch request.Method {
+	case "resources/subscribe":
+		w.Subscribe(sessionID, token, request.Params.URI)
+	case "resources/unsubscribe":
+		w.Unsubscribe(sessionID, request.Params.URI)
+	}
+}
+
+// ObserveStdin 把标准输入替换为管道，转发消息的同时记录stdio会话的订阅。
+// 必须在创建stdio传输之前调用
+func (w *Watcher) ObserveStdin(sessionID, token string) error {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	go func() {
+		defer writer.Close()
+		lines := bufio.NewReader(stdin)
+		for {
+			line, err := lines.ReadBytes('\n')
+			if len(line) > 0 {
+				w.Observe(sessionID, token, line)
+				if _, e := writer.Write(line); e != nil {
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					logger.Error("读取标准输入失败: %v", err)
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Run 按间隔轮询所有订阅，直到ctx结束
+func (w *Watcher) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll 查询每个订阅对象的状态，与上次结果不同时通知订阅的会话。第一次查询只记录状态
+func (w *Watcher) poll(ctx context.Context) {
+	w.mu.Lock()
+	targets := make(map[target]struct{})
+	for _, s := range w.sessions {
+		for uri := range s.uris {
+			targets[target{token: s.token, uri: uri}] = struct{}{}
+		}
+	}
+	notifiers := append([]Notifier(nil), w.notifiers...)
+	w.mu.Unlock()
+
+	ctx = withTickCache(ctx)
+	changed := make(map[string]struct{})
+	for t := range targets {
+		if w.check(ctx, t) {
+			changed[t.uri] = struct{}{}
+		}
+	}
+
+	for uri := range changed {
+		logger.Info("订阅资源 %s 已变化，发送更新通知", uri)
+		for _, notify := range notifiers {
+			if err := notify(ctx, uri); err != nil {
+				logger.Warn("发送资源 %s 的更新通知失败: %v", uri, err)
+			}
+		}
+	}
+}
+
+// check 查询一个对象的状态并记录摘要，返回与上次记录相比是否变化。第一次查询只记录状态
+func (w *Watcher) check(ctx context.Context, t target) bool {
+	probe, args, ok := w.match(t.uri)
+	if !ok {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(api.ContextWithToken(ctx, t.token), probeTimeout)
+	defer cancel()
+	client, err := w.clients.FromContext(ctx)
+	if err != nil {
+		logger.Warn("查询订阅资源 %s 的状态失败: %v", t.uri, err)
+		return false
+	}
+	digest, err := probe(ctx, client, args)
+	if err != nil {
+		logger.Warn("查询订阅资源 %s 的状态失败: %v", t.uri, err)
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.subscribed(t) {
+		// 查询期间订阅已被取消
+		return false
+	}
+	previous, seen := w.digests[t]
+	w.digests[t] = digest
+	return seen && previous != digest
+}
+
+// subscribed 判断是否仍有会话使用该令牌订阅资源，调用方需持有锁
+func (w *Watcher) subscribed(t target) bool {
+	for _, s := range w.sessions {
+		if _, ok := s.uris[t.uri]; ok && s.token == t.token {
+			return true
+		}
+	}
+	return false
+}
+
+// match 查找与uri匹配的状态查询函数并提取模板变量
+func (w *Watcher) match(uri string) (Probe, map[string]string, bool) {
+	segments := strings.Split(uri, "/")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, p := range w.probes {
+		if args, ok := matchSegments(p.segments, segments); ok {
+			return p.probe, args, true
+		}
+	}
+	return nil, nil, false
+}
+
+func matchSegments(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	args := make(map[string]string)
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			args[p[1:len(p)-1]] = value
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+// pruneDigests 删除已无人订阅的状态摘要，调用方需持有锁
+func (w *Watcher) pruneDigests() {
+	for t := range w.digests {
+		if !w.subscribed(t) {
+			delete(w.digests, t)
+		}
+	}
+}
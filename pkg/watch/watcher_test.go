@@ -0,0 +1,85 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rainmcp/pkg/api"
+)
+
+// TestWatcherNotifiesOnChange 验证订阅记录、共享轮询和状态变化通知
+func TestWatcherNotifiesOnChange(t *testing.T) {
+	w := New(api.NewPool("http://127.0.0.1:0"), time.Hour)
+	status := "running"
+	probes := 0
+	w.Handle("rainbond://teams/{team}/apps/{app_id}/components/{service_id}", func(_ context.Context, _ *api.Client, args map[string]string) (string, error) {
+		probes++
+		if args["service_id"] != "web" || args["team"] != "开发" {
+			t.Errorf("模板变量不正确: %v", args)
+		}
+		return status, nil
+	})
+	var notified []string
+	w.AddNotifier(func(_ context.Context, uri string) error {
+		notified = append(notified, uri)
+		return nil
+	})
+
+	uri := "rainbond://teams/%E5%BC%80%E5%8F%91/apps/7/components/web"
+	// 直接记录订阅，避免Subscribe中的异步查询干扰计数
+	w.sessions["a"] = &session{token: "t", uris: map[string]struct{}{uri: {}}}
+	w.sessions["b"] = &session{token: "t", uris: map[string]struct{}{uri: {}}}
+	w.Observe("c", "t", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"rainbond://regions"}}`))
+	if _, ok := w.sessions["c"]; ok {
+		t.Fatal("不支持状态推送的资源不应记录订阅")
+	}
+
+	w.poll(context.Background())
+	if probes != 1 || len(notified) != 0 {
+		t.Fatalf("第一次轮询只记录状态且同一令牌只查询一次: probes=%d notified=%v", probes, notified)
+	}
+	status = "closed"
+	w.poll(context.Background())
+	if len(notified) != 1 || notified[0] != uri {
+		t.Fatalf("状态变化后应发送一次通知，实际为 %v", notified)
+	}
+
+	w.Observe("a", "t", []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"`+uri+`"}}`))
+	w.Forget("b")
+	if len(w.sessions) != 0 || len(w.digests) != 0 {
+		t.Fatalf("取消订阅后应清除记录: %v %v", w.sessions, w.digests)
+	}
+}
+
+// TestCachedWithinTick 验证同一次轮询内相同令牌和key的查询只执行一次，轮询之外不缓存
+func TestCachedWithinTick(t *testing.T) {
+	fetches := 0
+	fetch := func() (string, error) {
+		fetches++
+		return "running", nil
+	}
+
+	tick := withTickCache(context.Background())
+	alice := api.ContextWithToken(tick, "alice")
+	for i := 0; i < 3; i++ {
+		if digest, err := Cached(alice, "dev/7/web", fetch); err != nil || digest != "running" {
+			t.Fatalf("Cached() = %q, %v", digest, err)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("同一次轮询内应只查询一次，实际 %d 次", fetches)
+	}
+
+	Cached(api.ContextWithToken(tick, "bob"), "dev/7/web", fetch)
+	Cached(alice, "dev/7/api", fetch)
+	if fetches != 3 {
+		t.Fatalf("不同令牌或key应分别查询，实际 %d 次", fetches)
+	}
+
+	Cached(api.ContextWithToken(withTickCache(context.Background()), "alice"), "dev/7/web", fetch)
+	Cached(api.ContextWithToken(context.Background(), "alice"), "dev/7/web", fetch)
+	if fetches != 5 {
+		t.Fatalf("新的轮询和轮询之外应重新查询，实际 %d 次", fetches)
+	}
+}